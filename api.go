@@ -0,0 +1,38 @@
+package telegraph
+
+// API is an interface for Telegraph API methods.
+//
+// It is implemented by *Client, and can be replaced with a fake (eg. telegraphtest.Fake) in tests.
+type API interface {
+	CreateAccount(shortName, authorName, authorURL string) (Account, error)
+	EditAccountInfo(shortName, authorName, authorURL string) (Account, error)
+	GetAccountInfo(fields []string) (Account, error)
+	RevokeAccessToken() (Account, error)
+	CreatePage(title, authorName, authorURL string, content []Node, returnContent bool) (Page, error)
+	EditPage(path, title string, content []Node, authorName, authorURL string, returnContent bool) (Page, error)
+	GetPage(path string, returnContent bool) (Page, error)
+	GetPageList(offset, limit int) (PageList, error)
+	GetViews(path string, year, month, day, hour int) (PageViews, error)
+}
+
+// check if *Client implements API
+var _ API = (*Client)(nil)
+
+// maximum `limit` of GetPageList
+const maxPageListLimit = 200
+
+// ListAllPages returns all pages of the account (without contents), the newest first,
+// by calling GetPageList repeatedly.
+func ListAllPages(client API) (pages []Page, err error) {
+	for {
+		var list PageList
+		if list, err = client.GetPageList(len(pages), maxPageListLimit); err != nil {
+			return pages, err
+		}
+		pages = append(pages, list.Pages...)
+
+		if len(list.Pages) == 0 || len(pages) >= list.TotalCount {
+			return pages, nil
+		}
+	}
+}
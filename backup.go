@@ -0,0 +1,239 @@
+package telegraph
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Account backup and restore
+//
+// Backup archive (zip or tar) contains:
+//
+//	manifest.json        list of pages with their files
+//	account.json         account information
+//	pages/<path>.json    page with its content nodes
+//	pages/<path>.html    rendered HTML of the page
+
+// BackupFormat is a format of backup archives.
+type BackupFormat string
+
+// BackupFormat constants
+const (
+	BackupFormatZip BackupFormat = "zip"
+	BackupFormatTar BackupFormat = "tar"
+)
+
+// version of backup archives
+const backupVersion = 1
+
+// BackupManifest is the manifest of a backup archive.
+type BackupManifest struct {
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	Account   Account       `json:"account"`
+	Pages     []BackupEntry `json:"pages"` // the newest first
+}
+
+// BackupEntry is a page in BackupManifest.
+type BackupEntry struct {
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	ContentFile string `json:"content_file"` // file of the page in JSON
+	HTMLFile    string `json:"html_file"`    // file of the rendered HTML
+}
+
+// Backup is a backup of an account.
+type Backup struct {
+	Manifest BackupManifest
+	Pages    []Page // with contents, in the same order as the manifest
+}
+
+// NewBackup fetches the account information and all pages with their contents.
+func NewBackup(client API) (backup Backup, err error) {
+	if backup.Manifest.Account, err = client.GetAccountInfo([]string{"short_name", "author_name", "author_url", "page_count"}); err != nil {
+		return backup, fmt.Errorf("failed to get account info: %w", err)
+	}
+	backup.Manifest.Version = backupVersion
+	backup.Manifest.CreatedAt = time.Now()
+
+	var pages []Page
+	if pages, err = ListAllPages(client); err != nil {
+		return backup, fmt.Errorf("failed to list pages: %w", err)
+	}
+
+	for _, page := range pages {
+		var fetched Page
+		if fetched, err = client.GetPage(page.Path, true); err != nil {
+			return backup, fmt.Errorf("failed to get page '%s': %w", page.Path, err)
+		}
+
+		escaped := url.PathEscape(page.Path)
+		backup.Manifest.Pages = append(backup.Manifest.Pages, BackupEntry{
+			Path:        fetched.Path,
+			URL:         fetched.URL,
+			Title:       fetched.Title,
+			ContentFile: "pages/" + escaped + ".json",
+			HTMLFile:    "pages/" + escaped + ".html",
+		})
+		backup.Pages = append(backup.Pages, fetched)
+	}
+
+	return backup, nil
+}
+
+// Write writes the backup as an archive of given format.
+func (b Backup) Write(w io.Writer, format BackupFormat) (err error) {
+	files := map[string][]byte{}
+
+	if files["manifest.json"], err = json.MarshalIndent(b.Manifest, "", "  "); err != nil {
+		return err
+	}
+	if files["account.json"], err = json.MarshalIndent(b.Manifest.Account, "", "  "); err != nil {
+		return err
+	}
+	for i, entry := range b.Manifest.Pages {
+		page := b.Pages[i]
+
+		if files[entry.ContentFile], err = json.MarshalIndent(page, "", "  "); err != nil {
+			return err
+		}
+		files[entry.HTMLFile] = fmt.Appendf(nil, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n<h1>%s</h1>\n%s\n</body>\n</html>\n",
+			html.EscapeString(page.Title), html.EscapeString(page.Title), RenderHTML(page.Content))
+	}
+
+	// manifest first, and others in order
+	names := sortedKeys(files)
+	names = slices.DeleteFunc(names, func(name string) bool { return name == "manifest.json" })
+	names = append([]string{"manifest.json"}, names...)
+
+	switch format {
+	case BackupFormatZip:
+		zw := zip.NewWriter(w)
+		for _, name := range names {
+			var fw io.Writer
+			if fw, err = zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: b.Manifest.CreatedAt}); err != nil {
+				return err
+			}
+			if _, err = fw.Write(files[name]); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	case BackupFormatTar:
+		tw := tar.NewWriter(w)
+		for _, name := range names {
+			if err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), ModTime: b.Manifest.CreatedAt}); err != nil {
+				return err
+			}
+			if _, err = tw.Write(files[name]); err != nil {
+				return err
+			}
+		}
+		return tw.Close()
+	}
+
+	return fmt.Errorf("unknown backup format: %s", format)
+}
+
+// ReadBackup reads a backup archive of given format.
+func ReadBackup(r io.Reader, format BackupFormat) (backup Backup, err error) {
+	files := map[string][]byte{}
+
+	switch format {
+	case BackupFormatZip:
+		var data []byte
+		if data, err = io.ReadAll(r); err != nil {
+			return backup, err
+		}
+
+		var zr *zip.Reader
+		if zr, err = zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+			return backup, fmt.Errorf("failed to read backup archive: %s", err)
+		}
+		for _, f := range zr.File {
+			var fr io.ReadCloser
+			if fr, err = f.Open(); err != nil {
+				return backup, err
+			}
+			files[f.Name], err = io.ReadAll(fr)
+			fr.Close()
+			if err != nil {
+				return backup, err
+			}
+		}
+	case BackupFormatTar:
+		tr := tar.NewReader(r)
+		for {
+			var header *tar.Header
+			if header, err = tr.Next(); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return backup, fmt.Errorf("failed to read backup archive: %s", err)
+			}
+			if files[header.Name], err = io.ReadAll(tr); err != nil {
+				return backup, err
+			}
+		}
+	default:
+		return backup, fmt.Errorf("unknown backup format: %s", format)
+	}
+
+	manifest, exists := files["manifest.json"]
+	if !exists {
+		return backup, errors.New("no manifest in backup archive")
+	}
+	if err = json.Unmarshal(manifest, &backup.Manifest); err != nil {
+		return backup, fmt.Errorf("failed to parse manifest: %s", err)
+	}
+	if backup.Manifest.Version > backupVersion {
+		return backup, fmt.Errorf("unsupported backup version: %d", backup.Manifest.Version)
+	}
+
+	for _, entry := range backup.Manifest.Pages {
+		content, exists := files[entry.ContentFile]
+		if !exists {
+			return backup, fmt.Errorf("no content file of page '%s' in backup archive", entry.Path)
+		}
+
+		var page Page
+		if err = json.Unmarshal(content, &page); err != nil {
+			return backup, fmt.Errorf("failed to parse page '%s': %s", entry.Path, err)
+		}
+		backup.Pages = append(backup.Pages, page)
+	}
+
+	return backup, nil
+}
+
+// RestoreBackup recreates pages of the backup with CreatePage (the oldest first),
+// and returns a mapping of old paths to new ones.
+//
+// For restoring to a new account, create a client with Create (eg. with the account in the manifest) and pass it.
+//
+// Failed pages do not stop the restore; they are returned as a joined error,
+// and are not included in the mapping.
+func RestoreBackup(client API, backup Backup) (mapping map[string]string, err error) {
+	mapping = map[string]string{}
+
+	errs := []error{}
+	for _, page := range slices.Backward(backup.Pages) {
+		created, err := client.CreatePage(page.Title, page.AuthorName, page.AuthorURL, page.Content, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore page '%s': %w", page.Path, err))
+			continue
+		}
+		mapping[page.Path] = created.Path
+	}
+
+	return mapping, errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package telegraph_test
+
+import (
+	"bytes"
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+	"github.com/meinside/telegraph-go/telegraphtest"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	source := telegraphtest.NewFake()
+	source.SetAccount(telegraph.Account{ShortName: "blog", AuthorName: "Author"})
+	source.AddPage(telegraph.Page{Path: "First-01-01", Title: "First", Content: []telegraph.Node{telegraph.NewNodeWithElement("p", nil, []telegraph.Node{"first"})}})
+	source.AddPage(telegraph.Page{Path: "Second-01-02", Title: "Second", Content: []telegraph.Node{"second"}})
+
+	backup, err := telegraph.NewBackup(source)
+	if err != nil {
+		t.Fatalf("failed to back up: %s", err)
+	}
+
+	for _, format := range []telegraph.BackupFormat{telegraph.BackupFormatZip, telegraph.BackupFormatTar} {
+		var buf bytes.Buffer
+		if err := backup.Write(&buf, format); err != nil {
+			t.Fatalf("failed to write %s backup: %s", format, err)
+		}
+
+		read, err := telegraph.ReadBackup(&buf, format)
+		if err != nil {
+			t.Fatalf("failed to read %s backup: %s", format, err)
+		}
+		if read.Manifest.Account.ShortName != "blog" || len(read.Pages) != 2 || telegraph.RenderHTML(read.Pages[1].Content) != "<p>first</p>" {
+			t.Fatalf("unexpected %s backup: %#+v", format, read)
+		}
+
+		target := telegraphtest.NewFake()
+		mapping, err := telegraph.RestoreBackup(target, read)
+		if err != nil || len(mapping) != 2 {
+			t.Fatalf("failed to restore %s backup: %#+v, %v", format, mapping, err)
+		}
+		if page, exists := target.Page(mapping["First-01-01"]); !exists || page.Title != "First" {
+			t.Errorf("unexpected restored page: %#+v", page)
+		}
+		if pages := target.Pages(); pages[0].Title != "First" {
+			t.Errorf("pages should be restored the oldest first: %#+v", pages)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Batch operations
+//
+// RunBatch creates or edits many pages concurrently, and reports results of each operation
+// instead of stopping at the first error:
+//
+//	results, err := telegraph.RunBatch(ctx, client, operations, telegraph.BatchOptions{
+//		Concurrency:    4,
+//		RateLimiter:    telegraph.NewRateLimiter(5, 1),
+//		CheckpointPath: "batch.checkpoint.json",
+//	})
+
+// BatchOperation is an operation of RunBatch: creating a new page, or editing an existing one.
+type BatchOperation struct {
+	ID string // unique id of the operation (required for checkpoints; default: its index)
+
+	Path       string // path of the page to edit (empty for creating a new page)
+	Title      string
+	AuthorName string
+	AuthorURL  string
+	Content    []Node
+}
+
+// BatchResult is the result of a BatchOperation.
+type BatchResult struct {
+	ID      string
+	Page    Page  // created or edited page (only path and url for skipped operations)
+	Err     error // error of the operation
+	Skipped bool  // operation was skipped, as it had been done in a previous run (see BatchOptions.CheckpointPath)
+}
+
+// BatchOptions is the options for RunBatch.
+type BatchOptions struct {
+	Concurrency int // number of concurrent workers (default: 4)
+
+	// RateLimiter limits the rate of operations (optional).
+	//
+	// It is also paused on FLOOD_WAIT_X errors, so share it with the client (WithRateLimiter) for pausing it too.
+	RateLimiter *RateLimiter
+
+	FloodWaitRetries int // number of retries of operations failed with FLOOD_WAIT_X errors (default: 3, negative = no retry)
+
+	// Progress is called after each operation with the number of finished ones (optional).
+	//
+	// It is called from worker goroutines, but not concurrently.
+	Progress func(done, total int, result BatchResult)
+
+	// CheckpointPath is a path of the checkpoint file (optional).
+	//
+	// Succeeded operations are saved in the file, and are skipped when run again with the same file.
+	// All operations should have unique IDs, as indices of operations may change between runs.
+	CheckpointPath string
+}
+
+// a succeeded operation in checkpoint files
+type batchCheckpoint struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// RunBatch runs given operations concurrently, and returns their results in the same order.
+//
+// Failed operations do not stop others; they are returned in results (and as a joined error).
+// Failures of saving checkpoints do not fail operations; they are only returned in the joined error.
+// When `ctx` is canceled, operations which are not started yet fail with its error.
+func RunBatch(ctx context.Context, client API, operations []BatchOperation, options BatchOptions) (results []BatchResult, err error) {
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+	if options.FloodWaitRetries == 0 {
+		options.FloodWaitRetries = 3
+	}
+
+	checkpoints := map[string]batchCheckpoint{} // id => checkpoint
+	if options.CheckpointPath != "" {
+		ids := map[string]bool{}
+		for i, operation := range operations {
+			if operation.ID == "" {
+				return nil, fmt.Errorf("id of operation %d is required for checkpoints", i)
+			} else if ids[operation.ID] {
+				return nil, fmt.Errorf("id of operation %d is duplicated: '%s'", i, operation.ID)
+			}
+			ids[operation.ID] = true
+		}
+
+		if checkpoints, err = loadBatchCheckpoints(options.CheckpointPath); err != nil {
+			return nil, err
+		}
+	}
+
+	operations = slices.Clone(operations)
+	results = make([]BatchResult, len(operations))
+	for i := range operations {
+		if operations[i].ID == "" {
+			operations[i].ID = strconv.Itoa(i)
+		}
+		results[i].ID = operations[i].ID
+	}
+
+	var mu sync.Mutex       // for checkpoints and progress
+	var checkpointErr error // error of the last save of checkpoints (saved ones include all previous checkpoints)
+	done := 0
+	finish := func(i int, result BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		results[i] = result
+		if result.Err == nil && !result.Skipped && options.CheckpointPath != "" {
+			checkpoints[result.ID] = batchCheckpoint{Path: result.Page.Path, URL: result.Page.URL}
+			checkpointErr = saveBatchCheckpoints(options.CheckpointPath, checkpoints)
+		}
+
+		done++
+		if options.Progress != nil {
+			options.Progress(done, len(operations), results[i])
+		}
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range min(options.Concurrency, max(len(operations), 1)) {
+		wg.Go(func() {
+			for i := range indices {
+				operation := operations[i]
+
+				mu.Lock()
+				checkpoint, exists := checkpoints[operation.ID]
+				mu.Unlock()
+				if exists {
+					finish(i, BatchResult{ID: operation.ID, Page: Page{Path: checkpoint.Path, URL: checkpoint.URL}, Skipped: true})
+					continue
+				}
+
+				page, err := runBatchOperation(ctx, client, operation, options)
+				finish(i, BatchResult{ID: operation.ID, Page: page, Err: err})
+			}
+		})
+	}
+
+	for i := range operations {
+		if ctx.Err() != nil {
+			finish(i, BatchResult{ID: operations[i].ID, Err: ctx.Err()})
+			continue
+		}
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	errs := []error{}
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("operation '%s' failed: %w", result.ID, result.Err))
+		}
+	}
+	if checkpointErr != nil {
+		errs = append(errs, checkpointErr)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// run an operation, retrying on FLOOD_WAIT_X errors
+func runBatchOperation(ctx context.Context, client API, operation BatchOperation, options BatchOptions) (page Page, err error) {
+	for retries := 0; ; retries++ {
+		if err = options.RateLimiter.WaitContext(ctx); err != nil {
+			return page, err
+		}
+
+		if operation.Path == "" {
+			page, err = client.CreatePage(operation.Title, operation.AuthorName, operation.AuthorURL, operation.Content, false)
+		} else {
+			page, err = client.EditPage(operation.Path, operation.Title, operation.Content, operation.AuthorName, operation.AuthorURL, false)
+		}
+
+		var floodWait *FloodWaitError
+		if !errors.As(err, &floodWait) || retries >= options.FloodWaitRetries {
+			return page, err
+		}
+
+		options.RateLimiter.Pause(floodWait.RetryAfter) // pause other workers too
+		if options.RateLimiter == nil {
+			timer := time.NewTimer(floodWait.RetryAfter)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return page, ctx.Err()
+			}
+		}
+	}
+}
+
+// load checkpoints from a file (empty if it does not exist)
+func loadBatchCheckpoints(path string) (checkpoints map[string]batchCheckpoint, err error) {
+	checkpoints = map[string]batchCheckpoint{}
+
+	var bytes []byte
+	if bytes, err = os.ReadFile(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return checkpoints, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint '%s': %s", path, err)
+	}
+	if err = json.Unmarshal(bytes, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint '%s': %s", path, err)
+	}
+
+	return checkpoints, nil
+}
+
+// save checkpoints to a file atomically
+func saveBatchCheckpoints(path string, checkpoints map[string]batchCheckpoint) (err error) {
+	var bytes []byte
+	if bytes, err = json.MarshalIndent(checkpoints, "", "  "); err == nil {
+		if err = os.WriteFile(path+".tmp", bytes, 0644); err == nil {
+			if err = os.Rename(path+".tmp", path); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to save checkpoint '%s': %s", path, err)
+}
@@ -0,0 +1,76 @@
+package telegraph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+	"github.com/meinside/telegraph-go/telegraphtest"
+)
+
+func TestRunBatch(t *testing.T) {
+	fake := telegraphtest.NewFake()
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	operations := []telegraph.BatchOperation{}
+	for i := range 10 {
+		operations = append(operations, telegraph.BatchOperation{ID: fmt.Sprintf("page-%d", i), Title: fmt.Sprintf("Page %d", i), Content: []telegraph.Node{"content"}})
+	}
+	operations = append(operations, telegraph.BatchOperation{ID: "edit", Path: "Missing-01-01", Title: "Missing", Content: []telegraph.Node{"content"}})
+
+	var progress atomic.Int32
+	options := telegraph.BatchOptions{
+		Concurrency:    3,
+		CheckpointPath: checkpoint,
+		Progress: func(done, total int, result telegraph.BatchResult) {
+			progress.Add(1)
+		},
+	}
+
+	// an operation fails, but others succeed
+	results, err := telegraph.RunBatch(context.Background(), fake, operations, options)
+	if err == nil || results[10].Err == nil || progress.Load() != 11 {
+		t.Fatalf("expected a failure: %v", err)
+	}
+	for _, result := range results[:10] {
+		if result.Err != nil || result.Page.Path == "" {
+			t.Errorf("unexpected result: %#+v", result)
+		}
+	}
+	if len(fake.Pages()) != 10 {
+		t.Errorf("unexpected number of pages: %d", len(fake.Pages()))
+	}
+
+	// resumed from the checkpoint
+	fake.AddPage(telegraph.Page{Path: "Missing-01-01", Title: "Missing"})
+	results, err = telegraph.RunBatch(context.Background(), fake, operations, options)
+	if err != nil || !results[0].Skipped || results[10].Skipped {
+		t.Fatalf("unexpected results: %#+v, %v", results, err)
+	}
+	if len(fake.CallsTo(telegraphtest.MethodCreatePage)) != 10 {
+		t.Errorf("created pages should not be created again")
+	}
+
+	// ids are required for checkpoints
+	if _, err = telegraph.RunBatch(context.Background(), fake, []telegraph.BatchOperation{{Title: "No ID"}}, options); err == nil {
+		t.Errorf("operations without ids should fail with checkpoints")
+	}
+
+	// checkpoint cannot be saved
+	options.CheckpointPath = filepath.Join(t.TempDir(), "missing", "checkpoint.json")
+	results, err = telegraph.RunBatch(context.Background(), fake, operations[:1], options)
+	if err == nil || results[0].Err != nil || results[0].Page.Path == "" {
+		t.Errorf("failure of saving checkpoints should not fail operations: %#+v, %v", results, err)
+	}
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if results, err = telegraph.RunBatch(ctx, fake, operations[:2], telegraph.BatchOptions{}); !errors.Is(err, context.Canceled) || !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("unexpected results: %#+v, %v", results, err)
+	}
+}
@@ -0,0 +1,179 @@
+package telegraph
+
+import "fmt"
+
+// Node builders
+//
+// Functions for building nodes with Telegraph's supported tags and attributes only:
+//
+//	content := []telegraph.Node{
+//		telegraph.H3("Release notes"),
+//		telegraph.P("Version ", telegraph.B("1.0"), " is out. See ", telegraph.A("https://example.com", "details"), "."),
+//		telegraph.UL("fixed bugs", telegraph.LI("added ", telegraph.Code("Foo()"))),
+//		telegraph.Figure(telegraph.Img("https://example.com/image.png"), "screenshot"),
+//	}
+//
+// Children can be strings, nodes, or []Node (which are flattened);
+// other values (eg. numbers) are programming errors, so builders panic on them.
+
+// A creates a link element.
+func A(href string, children ...Node) Node {
+	return NodeElement{Tag: "a", Attrs: map[string]string{"href": href}, Children: flattenNodes(children)}
+}
+
+// Aside creates an aside element.
+func Aside(children ...Node) Node {
+	return buildElement("aside", children)
+}
+
+// B creates a bold element.
+func B(children ...Node) Node {
+	return buildElement("b", children)
+}
+
+// Blockquote creates a blockquote element.
+func Blockquote(children ...Node) Node {
+	return buildElement("blockquote", children)
+}
+
+// BR creates a line break element.
+func BR() Node {
+	return NodeElement{Tag: "br"}
+}
+
+// Code creates an inline code element.
+func Code(children ...Node) Node {
+	return buildElement("code", children)
+}
+
+// Em creates an emphasis element.
+func Em(children ...Node) Node {
+	return buildElement("em", children)
+}
+
+// Figure creates a figure element with given media (eg. Img, Video, or Iframe) and an optional caption.
+func Figure(media Node, caption ...Node) Node {
+	children := []Node{media}
+	if caption = flattenNodes(caption); len(caption) > 0 {
+		children = append(children, buildElement("figcaption", caption))
+	}
+
+	return NodeElement{Tag: "figure", Children: children}
+}
+
+// H3 creates a heading element (the largest heading supported by Telegraph).
+func H3(children ...Node) Node {
+	return buildElement("h3", children)
+}
+
+// H4 creates a subheading element.
+func H4(children ...Node) Node {
+	return buildElement("h4", children)
+}
+
+// HR creates a horizontal rule element.
+func HR() Node {
+	return NodeElement{Tag: "hr"}
+}
+
+// I creates an italic element.
+func I(children ...Node) Node {
+	return buildElement("i", children)
+}
+
+// Iframe creates an iframe element (see also NewEmbed).
+func Iframe(src string) Node {
+	return NodeElement{Tag: "iframe", Attrs: map[string]string{"src": src}}
+}
+
+// Img creates an image element.
+func Img(src string) Node {
+	return NodeElement{Tag: "img", Attrs: map[string]string{"src": src}}
+}
+
+// LI creates a list item element.
+func LI(children ...Node) Node {
+	return buildElement("li", children)
+}
+
+// OL creates an ordered list element.
+//
+// Items which are not list item elements are wrapped with LI.
+func OL(items ...Node) Node {
+	return NodeElement{Tag: "ol", Children: listItems(items)}
+}
+
+// P creates a paragraph element.
+func P(children ...Node) Node {
+	return buildElement("p", children)
+}
+
+// Pre creates a preformatted text element.
+func Pre(code string) Node {
+	return NodeElement{Tag: "pre", Children: []Node{code}}
+}
+
+// S creates a strikethrough element.
+func S(children ...Node) Node {
+	return buildElement("s", children)
+}
+
+// Strong creates a strong element.
+func Strong(children ...Node) Node {
+	return buildElement("strong", children)
+}
+
+// U creates an underline element.
+func U(children ...Node) Node {
+	return buildElement("u", children)
+}
+
+// UL creates an unordered list element.
+//
+// Items which are not list item elements are wrapped with LI.
+func UL(items ...Node) Node {
+	return NodeElement{Tag: "ul", Children: listItems(items)}
+}
+
+// Video creates a video element.
+func Video(src string) Node {
+	return NodeElement{Tag: "video", Attrs: map[string]string{"src": src}}
+}
+
+// build an element with given tag and children
+func buildElement(tag string, children []Node) Node {
+	return NodeElement{Tag: tag, Children: flattenNodes(children)}
+}
+
+// wrap items with list item elements
+func listItems(items []Node) []Node {
+	wrapped := []Node{}
+	for _, item := range flattenNodes(items) {
+		if li, ok := item.(NodeElement); ok && li.Tag == "li" {
+			wrapped = append(wrapped, li)
+		} else {
+			wrapped = append(wrapped, LI(item))
+		}
+	}
+
+	return wrapped
+}
+
+// flatten []Node children, and panic on values which are not nodes
+func flattenNodes(children []Node) []Node {
+	flattened := []Node{}
+	for _, child := range children {
+		switch c := normalizeNode(child).(type) {
+		case nil:
+			continue
+		case []Node:
+			flattened = append(flattened, flattenNodes(c)...)
+		case string, NodeElement:
+			flattened = append(flattened, c)
+		default:
+			panic(fmt.Sprintf("telegraph: unsupported node of type %T: %v", c, c))
+		}
+	}
+
+	return flattened
+}
@@ -0,0 +1,29 @@
+package telegraph
+
+import "testing"
+
+func TestBuilders(t *testing.T) {
+	content := []Node{
+		H3("Release ", "1.5"),
+		P("See ", A("https://example.com", B("details")), BR(), []Node{"flattened", nil}),
+		UL("one", LI("two", Code("x"))),
+		Figure(Img("https://example.com/a.png"), "caption"),
+		Pre("a < b"),
+	}
+
+	expected := `<h3>Release 1.5</h3><p>See <a href="https://example.com"><b>details</b></a><br>flattened</p><ul><li>one</li><li>two<code>x</code></li></ul><figure><img src="https://example.com/a.png"><figcaption>caption</figcaption></figure><pre>a &lt; b</pre>`
+	if html := RenderHTML(content); html != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", html, expected)
+	}
+	if sanitized := RenderHTML(SanitizeNodes(content)); sanitized != expected {
+		t.Errorf("built nodes should be valid:\n%s", sanitized)
+	}
+
+	// only nodes are allowed
+	defer func() {
+		if recover() == nil {
+			t.Errorf("builders should panic on values which are not nodes")
+		}
+	}()
+	H3("Release ", 1.5)
+}
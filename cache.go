@@ -0,0 +1,262 @@
+package telegraph
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Read-through caching
+//
+// Results of GetPage, GetPageList, and GetViews are cached when a Cache is set with WithCache.
+// Cached pages of a path are invalidated when the page is edited with EditPage (including the ones being fetched
+// while editing), and cached page lists
+// are invalidated when any page is created or edited through the same client.
+// Concurrent identical requests are deduplicated into one.
+//
+// Cached values are shared, so the contents of returned pages should not be modified.
+
+// Cache is an interface for cache backends.
+type Cache interface {
+	// Get returns the cached value of given key, if it exists and is not expired.
+	Get(key string) (value any, ok bool)
+
+	// Set caches given value for `ttl`.
+	Set(key string, value any, ttl time.Duration)
+
+	// Delete removes the cached value of given key.
+	Delete(key string)
+}
+
+// WithCache sets a Cache for caching results of GetPage, GetPageList, and GetViews for `ttl`.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// fetch a value through the client's cache, deduplicating concurrent identical fetches
+//
+// (`key` is called only when the client has a cache)
+func cached[T any](c *Client, key func() string, fetch func() (T, error)) (T, error) {
+	if c == nil || c.cache == nil {
+		return fetch()
+	}
+
+	k := key()
+	if value, ok := c.cache.Get(k); ok {
+		if result, ok := value.(T); ok {
+			return result, nil
+		}
+	}
+
+	value, err := c.flights.do(k, func() (any, error) {
+		return fetch()
+	}, func(value any) {
+		c.cache.Set(k, value, c.cacheTTL)
+	})
+
+	result, _ := value.(T)
+	return result, err
+}
+
+// invalidate cached values related to the page at given path
+func (c *Client) invalidatePage(path string) {
+	if c == nil || c.cache == nil {
+		return
+	}
+
+	for _, key := range []string{pageCacheKey(path, true), pageCacheKey(path, false)} {
+		c.flights.forget(key) // pages being fetched before the edit will not be cached
+		c.cache.Delete(key)
+	}
+	c.invalidatePageList()
+}
+
+// invalidate cached page lists of the client
+func (c *Client) invalidatePageList() {
+	if c == nil || c.cache == nil {
+		return
+	}
+
+	c.listGeneration.Add(1)
+}
+
+// cache key of a page
+func pageCacheKey(path string, returnContent bool) string {
+	return fmt.Sprintf("getPage/%s?return_content=%t", path, returnContent)
+}
+
+// cache key of a page list
+func (c *Client) pageListCacheKey(offset, limit int) string {
+	if c == nil {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(c.AccessToken())) // do not put access tokens in cache backends
+
+	return fmt.Sprintf("getPageList/%s/%d?offset=%d&limit=%d", hex.EncodeToString(hash[:8]), c.listGeneration.Load(), offset, limit)
+}
+
+// cache key of page views
+func viewsCacheKey(path string, year, month, day, hour int) string {
+	return fmt.Sprintf("getViews/%s?year=%d&month=%d&day=%d&hour=%d", path, year, month, day, hour)
+}
+
+////////////////
+// deduplication of concurrent calls
+
+// group of in-flight calls
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// an in-flight call
+type flightCall struct {
+	wg        sync.WaitGroup
+	value     any
+	err       error
+	forgotten bool // result is outdated, so it should not be stored (guarded by flightGroup.mu)
+}
+
+// call `fn` once for concurrent calls with the same key, and `store` its successful result unless the key is forgotten
+func (g *flightGroup) do(key string, fn func() (any, error), store func(any)) (any, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if call, exists := g.calls[key]; exists {
+		g.mu.Unlock()
+		call.wg.Wait()
+
+		return call.value, call.err
+	}
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.value, call.err = fn()
+
+	g.mu.Lock()
+	if !call.forgotten {
+		delete(g.calls, key)
+		if call.err == nil {
+			store(call.value)
+		}
+	}
+	g.mu.Unlock()
+	call.wg.Done()
+
+	return call.value, call.err
+}
+
+// forget an in-flight call with given key, so that its result is not stored and later calls do not wait for it
+func (g *flightGroup) forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, exists := g.calls[key]; exists {
+		call.forgotten = true
+		delete(g.calls, key)
+	}
+}
+
+////////////////
+// LRU cache
+
+// LRUCache is an in-memory Cache which evicts least recently used values when it is full.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  *list.List
+	items    map[string]*list.Element
+}
+
+// entry of LRUCache
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// NewLRUCache creates a new LRUCache which keeps at most `capacity` values.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: max(capacity, 1),
+		entries:  list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+// Get returns the cached value of given key.
+func (c *LRUCache) Get(key string) (value any, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.items[key]; exists {
+		entry := element.Value.(*lruEntry)
+		if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+			c.remove(element)
+			return nil, false
+		}
+
+		c.entries.MoveToFront(element)
+		return entry.value, true
+	}
+
+	return nil, false
+}
+
+// Set caches given value for `ttl` (0 for no expiration).
+func (c *LRUCache) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, exists := c.items[key]; exists {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.entries.Len() > c.capacity {
+		c.remove(c.entries.Back())
+	}
+}
+
+// Delete removes the cached value of given key.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.items[key]; exists {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of cached values (including expired ones not removed yet).
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.Len()
+}
+
+// remove an element
+func (c *LRUCache) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.items, element.Value.(*lruEntry).key)
+}
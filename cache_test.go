@@ -0,0 +1,124 @@
+package telegraph
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+	cache.Get("a") // "b" becomes the least recently used one
+	cache.Set("c", 3, 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("least recently used value should be evicted")
+	}
+	if value, ok := cache.Get("a"); !ok || value != 1 {
+		t.Errorf("unexpected value: %v, %t", value, ok)
+	}
+
+	cache.Set("expiring", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("expiring"); ok {
+		t.Errorf("expired value should not be returned")
+	}
+
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("deleted value should not be returned")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	transport := &cannedTransport{responses: []string{
+		`{"ok":true,"result":{"path":"Sample","title":"Original"}}`,
+		`{"ok":true,"result":{"path":"Sample","title":"Edited"}}`,
+		`{"ok":true,"result":{"path":"Sample","title":"Edited"}}`,
+	}}
+	client := NewClient("token", WithHTTPClient(&http.Client{Transport: transport}), WithCache(NewLRUCache(10), time.Minute))
+
+	for range 3 {
+		if page, err := client.GetPage("Sample", false); err != nil || page.Title != "Original" {
+			t.Fatalf("unexpected page: %#+v, %v", page, err)
+		}
+	}
+	if transport.requests != 1 {
+		t.Errorf("expected 1 request, but sent %d", transport.requests)
+	}
+
+	// cached page is invalidated on edit
+	if _, err := client.EditPage("Sample", "Edited", nil, "", "", false); err != nil {
+		t.Fatalf("failed to edit page: %s", err)
+	}
+	if page, _ := client.GetPage("Sample", false); page.Title != "Edited" || transport.requests != 3 {
+		t.Errorf("unexpected page after edit: %#+v (%d requests)", page, transport.requests)
+	}
+}
+
+func TestClientCacheStaleFetch(t *testing.T) {
+	transport := &cannedTransport{responses: []string{`{"ok":true,"result":{"path":"Sample","title":"Edited"}}`}}
+	client := NewClient("token", WithHTTPClient(&http.Client{Transport: transport}), WithCache(NewLRUCache(10), time.Minute))
+
+	// page is edited while fetching it
+	cached(client, func() string { return pageCacheKey("Sample", false) }, func() (Page, error) {
+		client.invalidatePage("Sample")
+		return Page{Path: "Sample", Title: "Original"}, nil
+	})
+
+	if page, err := client.GetPage("Sample", false); err != nil || page.Title != "Edited" || transport.requests != 1 {
+		t.Errorf("stale page should not be cached: %#+v, %v (%d requests)", page, err, transport.requests)
+	}
+}
+
+func TestClientWithoutCache(t *testing.T) {
+	client := NewClient("token")
+
+	if value, _ := cached(client, func() string {
+		t.Errorf("cache key should not be generated without a cache")
+		return ""
+	}, func() (int, error) {
+		return 1, nil
+	}); value != 1 {
+		t.Errorf("unexpected value: %d", value)
+	}
+}
+
+// RoundTripper which blocks until released
+type blockingTransport struct {
+	cannedTransport
+	release chan struct{}
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-t.release
+	return t.cannedTransport.RoundTrip(req)
+}
+
+func TestClientCacheDeduplication(t *testing.T) {
+	transport := &blockingTransport{
+		cannedTransport: cannedTransport{responses: []string{`{"ok":true,"result":{"views":3}}`}},
+		release:         make(chan struct{}),
+	}
+	client := NewClient("", WithHTTPClient(&http.Client{Transport: transport}), WithCache(NewLRUCache(10), time.Minute))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if views, err := client.GetViews("Sample", 2024, 0, 0, -1); err != nil || views.Views != 3 {
+				t.Errorf("unexpected views: %#+v, %v", views, err)
+			}
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(transport.release)
+	wg.Wait()
+
+	if transport.requests != 1 {
+		t.Errorf("expected 1 request, but sent %d", transport.requests)
+	}
+}
@@ -0,0 +1,251 @@
+package telegraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Record-and-replay of HTTP interactions
+//
+// Recorder is a http.RoundTripper which captures requests and responses into a cassette file,
+// and Replayer serves them back, so tests around Telegraph integrations can run offline:
+//
+//	recorder := telegraph.NewRecorder("testdata/cassette.json", nil)
+//	client := telegraph.NewClient(token, telegraph.WithHTTPClient(&http.Client{Transport: recorder}))
+//	...
+//	recorder.Save()
+//
+//	replayer, _ := telegraph.NewReplayer("testdata/cassette.json")
+//	client := telegraph.NewClient(token, telegraph.WithHTTPClient(&http.Client{Transport: replayer}))
+//
+// Access tokens and auth urls are scrubbed from recorded requests and responses.
+
+// Cassette is a list of recorded HTTP interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded pair of HTTP request and response.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded HTTP request.
+type CassetteRequest struct {
+	Method string              `json:"method"` // HTTP method
+	Path   string              `json:"path"`   // URL path (eg. "/getPage/Sample-Page-12-15")
+	Form   map[string][]string `json:"form"`   // form params (scrubbed)
+}
+
+// CassetteResponse is a recorded HTTP response.
+type CassetteResponse struct {
+	Status int    `json:"status"`
+	Body   string `json:"body"` // response body (scrubbed)
+}
+
+////////////////
+// recorder
+
+// Recorder is a http.RoundTripper which records HTTP interactions.
+type Recorder struct {
+	mu        sync.Mutex
+	path      string
+	transport http.RoundTripper
+	cassette  Cassette
+}
+
+// NewRecorder creates a new Recorder which sends requests with given transport
+// (or the default one if nil), and saves interactions to a cassette file at `path`.
+func NewRecorder(path string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = defaultHTTPClient.Transport
+	}
+
+	return &Recorder{
+		path:      path,
+		transport: transport,
+	}
+}
+
+// RoundTrip sends given request, and records it with its response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newCassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: CassetteResponse{
+			Status: res.StatusCode,
+			Body:   redactString(string(body)),
+		},
+	})
+
+	return res, nil
+}
+
+// Cassette returns a copy of the recorded interactions.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Cassette{Interactions: slices.Clone(r.cassette.Interactions)}
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	bytes, err := json.MarshalIndent(r.Cassette(), "", "  ")
+	if err == nil {
+		if err = os.WriteFile(r.path, bytes, 0644); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to save cassette '%s': %s", r.path, err)
+}
+
+////////////////
+// replayer
+
+// Replayer is a http.RoundTripper which serves recorded HTTP interactions.
+//
+// Requests are matched with recorded ones by HTTP method, URL path, and normalized form params,
+// and each recorded interaction is served only once, in the recorded order.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewReplayer creates a new Replayer with the cassette file at `path`.
+func NewReplayer(path string) (*Replayer, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette '%s': %s", path, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(bytes, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette '%s': %s", path, err)
+	}
+
+	return NewReplayerWithCassette(cassette), nil
+}
+
+// NewReplayerWithCassette creates a new Replayer with given cassette.
+func NewReplayerWithCassette(cassette Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// RoundTrip returns the recorded response of a matching request.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	requested, err := newCassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	key := requested.key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] && interaction.Request.key() == key {
+			r.used[i] = true
+
+			return &http.Response{
+				Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+				StatusCode:    interaction.Response.Status,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        http.Header{"Content-Type": []string{"application/json"}},
+				Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+				ContentLength: int64(len(interaction.Response.Body)),
+				Request:       req,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for request: %s %s", requested.Method, requested.Path)
+}
+
+// Unused returns recorded interactions which were not served yet.
+func (r *Replayer) Unused() (unused []Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+// create a scrubbed CassetteRequest from given request (its body is restored after reading)
+func newCassetteRequest(req *http.Request) (recorded CassetteRequest, err error) {
+	form := url.Values{}
+
+	if req.Body != nil {
+		var body []byte
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return recorded, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if form, err = url.ParseQuery(string(body)); err != nil {
+			return recorded, fmt.Errorf("failed to parse request body: %s", err)
+		}
+	}
+
+	for key, values := range form {
+		for i, value := range values {
+			if slices.Contains(secretKeys, key) {
+				values[i] = redacted
+			} else {
+				values[i] = redactString(value)
+			}
+		}
+	}
+
+	return CassetteRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Form:   form,
+	}, nil
+}
+
+// key for matching requests
+func (r CassetteRequest) key() string {
+	return r.Method + " " + r.Path + "?" + url.Values(r.Form).Encode()
+}
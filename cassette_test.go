@@ -0,0 +1,53 @@
+package telegraph
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// record
+	recorder := NewRecorder(path, &cannedTransport{responses: []string{
+		`{"ok":true,"result":{"short_name":"test","access_token":"secret-token","auth_url":"https://edit.telegra.ph/auth/secret"}}`,
+		`{"ok":true,"result":{"path":"Test-01-01","title":"Test","views":0}}`,
+	}})
+	client := NewClient("", WithHTTPClient(&http.Client{Transport: recorder}))
+	if _, err := client.CreateAccount("test", "", ""); err != nil {
+		t.Fatalf("failed to create account: %s", err)
+	}
+	client.SetAccessToken("secret-token")
+	if _, err := client.CreatePage("Test", "", "", []Node{"content"}, false); err != nil {
+		t.Fatalf("failed to create page: %s", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("failed to save cassette: %s", err)
+	}
+
+	if bytes, err := os.ReadFile(path); err != nil || strings.Contains(string(bytes), "secret") {
+		t.Errorf("secrets were not scrubbed: %s, %v", bytes, err)
+	}
+
+	// replay
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("failed to load cassette: %s", err)
+	}
+	client = NewClient("another-token", WithHTTPClient(&http.Client{Transport: replayer}))
+	if _, err := client.CreateAccount("test", "", ""); err != nil {
+		t.Errorf("failed to replay account creation: %s", err)
+	}
+	if page, err := client.CreatePage("Test", "", "", []Node{"content"}, false); err != nil || page.Path != "Test-01-01" {
+		t.Errorf("failed to replay page creation: %#+v, %v", page, err)
+	}
+	if _, err := client.CreatePage("Different title", "", "", []Node{"content"}, false); err == nil {
+		t.Errorf("should fail with unrecorded request")
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("unexpected unused interactions: %#+v", unused)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+// run `account` command
+func runAccount(args []string) error {
+	if len(args) == 0 {
+		return errors.New("account: subcommand required (create, info, edit, revoke)")
+	}
+
+	switch args[0] {
+	case "create":
+		return runAccountCreate(args[1:])
+	case "info":
+		return runAccountInfo(args[1:])
+	case "edit":
+		return runAccountEdit(args[1:])
+	case "revoke":
+		return runAccountRevoke(args[1:])
+	}
+
+	return fmt.Errorf("account: unknown subcommand: %s", args[0])
+}
+
+// `account create`
+func runAccountCreate(args []string) error {
+	fs := flag.NewFlagSet("account create", flag.ContinueOnError)
+	shortName := fs.String("short-name", "", "short name of the account (1-32 characters, required)")
+	authorName := fs.String("author-name", "", "default author name (0-128 characters)")
+	authorURL := fs.String("author-url", "", "default author url (0-512 characters)")
+	save := fs.Bool("save", false, "save the access token to the config file")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *shortName == "" {
+		return errors.New("account create: -short-name is required")
+	}
+
+	client, err := telegraph.Create(*shortName, *authorName, *authorURL)
+	if err != nil {
+		return err
+	}
+	if *save {
+		if err := saveAccessToken(client.AccessToken()); err != nil {
+			return err
+		}
+	}
+
+	account := telegraph.Account{
+		ShortName:   *shortName,
+		AuthorName:  *authorName,
+		AuthorURL:   *authorURL,
+		AccessToken: client.AccessToken(),
+	}
+	return out.print(account, nil, accountRows(account))
+}
+
+// `account info`
+func runAccountInfo(args []string) error {
+	fs := flag.NewFlagSet("account info", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	fields := fs.String("fields", "short_name,author_name,author_url,auth_url,page_count", "comma-separated fields to fetch")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+
+	account, err := client.GetAccountInfo(splitNonEmpty(*fields, ","))
+	if err != nil {
+		return err
+	}
+	return out.print(account, nil, accountRows(account))
+}
+
+// `account edit`
+func runAccountEdit(args []string) error {
+	fs := flag.NewFlagSet("account edit", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	shortName := fs.String("short-name", "", "new short name (1-32 characters, required)")
+	authorName := fs.String("author-name", "", "new default author name (0-128 characters)")
+	authorURL := fs.String("author-url", "", "new default author url (0-512 characters)")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *shortName == "" {
+		return errors.New("account edit: -short-name is required")
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+
+	account, err := client.EditAccountInfo(*shortName, *authorName, *authorURL)
+	if err != nil {
+		return err
+	}
+	return out.print(account, nil, accountRows(account))
+}
+
+// `account revoke`
+func runAccountRevoke(args []string) error {
+	fs := flag.NewFlagSet("account revoke", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	save := fs.Bool("save", false, "save the new access token to the config file")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+
+	account, err := client.RevokeAccessToken()
+	if err != nil {
+		return err
+	}
+	if *save {
+		if err := saveAccessToken(account.AccessToken); err != nil {
+			return err
+		}
+	}
+	return out.print(account, nil, accountRows(account))
+}
+
+// save access token to the config file
+func saveAccessToken(token string) error {
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	conf.AccessToken = token
+
+	path, err := saveConfig(conf)
+	if err != nil {
+		return fmt.Errorf("failed to save config file '%s': %s", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "access token saved to: %s\n", path)
+
+	return nil
+}
+
+// table rows for an account
+func accountRows(account telegraph.Account) [][]string {
+	rows := keyValueRows(
+		"short name", account.ShortName,
+		"author name", account.AuthorName,
+		"author url", account.AuthorURL,
+	)
+	if account.AccessToken != "" {
+		rows = append(rows, keyValueRows("access token", account.AccessToken)...)
+	}
+	if account.AuthURL != "" {
+		rows = append(rows, keyValueRows("auth url", account.AuthURL)...)
+	}
+	if account.PageCount > 0 {
+		rows = append(rows, keyValueRows("page count", strconv.Itoa(account.PageCount))...)
+	}
+	return rows
+}
+
+// split given string with separator, and drop empty ones
+func splitNonEmpty(str, sep string) (splitted []string) {
+	for _, s := range strings.Split(str, sep) {
+		if s = strings.TrimSpace(s); s != "" {
+			splitted = append(splitted, s)
+		}
+	}
+	return splitted
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+// run `backup` command
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	output := fs.String("out", "", "path of the backup archive: .zip or .tar (required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *output == "" {
+		return errors.New("backup: -out is required")
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+
+	backup, err := telegraph.NewBackup(client)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := backup.Write(file, backupFormat(*output)); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("backed up %d pages to %s\n", len(backup.Pages), *output)
+	return nil
+}
+
+// run `restore` command
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	input := fs.String("in", "", "path of the backup archive: .zip or .tar (required)")
+	newAccount := fs.Bool("new-account", false, "restore to a new account created with the account info in the backup")
+	save := fs.Bool("save", false, "save the access token of the new account to the config file")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return errors.New("restore: -in is required")
+	}
+
+	file, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	backup, err := telegraph.ReadBackup(file, backupFormat(*input))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	var client *telegraph.Client
+	if *newAccount {
+		account := backup.Manifest.Account
+		if client, err = telegraph.Create(account.ShortName, account.AuthorName, account.AuthorURL); err != nil {
+			return err
+		}
+		if *save {
+			if err := saveAccessToken(client.AccessToken()); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "created a new account with access token: %s\n", client.AccessToken())
+	} else if client, err = clientWithToken(*token); err != nil {
+		return err
+	}
+
+	mapping, restoreErr := telegraph.RestoreBackup(client, backup)
+
+	rows := [][]string{}
+	for _, entry := range backup.Manifest.Pages {
+		if path, exists := mapping[entry.Path]; exists {
+			rows = append(rows, []string{entry.Path, path})
+		}
+	}
+	if err := out.print(mapping, []string{"OLD PATH", "NEW PATH"}, rows); err != nil {
+		return err
+	}
+	if !out.json {
+		fmt.Printf("(%d of %d pages restored)\n", len(mapping), len(backup.Pages))
+	}
+
+	return restoreErr
+}
+
+// format of a backup archive from its file extension
+func backupFormat(path string) telegraph.BackupFormat {
+	if strings.EqualFold(filepath.Ext(path), ".tar") {
+		return telegraph.BackupFormatTar
+	}
+
+	return telegraph.BackupFormatZip
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+func TestBackupFormat(t *testing.T) {
+	for path, expected := range map[string]telegraph.BackupFormat{
+		"backup.zip":     telegraph.BackupFormatZip,
+		"backup.tar":     telegraph.BackupFormatTar,
+		"BACKUP.TAR":     telegraph.BackupFormatTar,
+		"backup.tar.gz":  telegraph.BackupFormatZip,
+		"backup":         telegraph.BackupFormatZip,
+		"dir.tar/backup": telegraph.BackupFormatZip,
+	} {
+		if format := backupFormat(path); format != expected {
+			t.Errorf("unexpected format of '%s': %v", path, format)
+		}
+	}
+}
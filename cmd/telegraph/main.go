@@ -0,0 +1,180 @@
+package main
+
+// telegraph-go/cmd/telegraph
+//
+// Command-line tool for Telegraph API.
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+// constants
+const (
+	envAccessToken = "TELEGRAPH_ACCESS_TOKEN"
+	envConfigPath  = "TELEGRAPH_CONFIG"
+	envVerbose     = "TELEGRAPH_VERBOSE"
+
+	configDirName  = "telegraph-go"
+	configFileName = "config.json"
+)
+
+const usage = `Usage: telegraph <command> [<subcommand>] [flags]
+
+Commands:
+  account create   create a new account (-save to store its access token)
+  account info     show account information
+  account edit     edit account information
+  account revoke   revoke the access token and show a new one (-save to store it)
+
+  page create      create a new page from HTML/Markdown file or stdin
+  page edit        edit an existing page from HTML/Markdown file or stdin
+  page get         show a page
+  page list        list pages of the account
+
+  views            show the number of views of a page
+
+  backup           back up all pages of the account to a .zip or .tar archive
+  restore          restore pages from a backup archive (-new-account to create a new account)
+
+Access token is read from (in order):
+  -token flag, $TELEGRAPH_ACCESS_TOKEN, and the config file
+  ($TELEGRAPH_CONFIG or $XDG_CONFIG_HOME/telegraph-go/config.json)
+
+Run 'telegraph <command> [<subcommand>] -h' for the flags of each command.
+`
+
+// config struct
+type config struct {
+	AccessToken string `json:"access_token"`
+}
+
+func main() {
+	telegraph.Verbose = os.Getenv(envVerbose) == "true"
+
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run command with given arguments
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errors.New("no command given")
+	}
+
+	switch args[0] {
+	case "account":
+		return runAccount(args[1:])
+	case "page":
+		return runPage(args[1:])
+	case "views":
+		return runViews(args[1:])
+	case "backup":
+		return runBackup(args[1:])
+	case "restore":
+		return runRestore(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command: %s", args[0])
+}
+
+// path of the config file
+func configPath() (string, error) {
+	if path := os.Getenv(envConfigPath); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName, configFileName), nil
+}
+
+// load config from the config file
+func loadConfig() (conf config, err error) {
+	var path string
+	if path, err = configPath(); err != nil {
+		return conf, err
+	}
+
+	var bytes []byte
+	if bytes, err = os.ReadFile(path); err == nil {
+		if err = json.Unmarshal(bytes, &conf); err != nil {
+			err = fmt.Errorf("failed to parse config file '%s': %s", path, err)
+		}
+	} else if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+
+	return conf, err
+}
+
+// save config to the config file
+func saveConfig(conf config) (path string, err error) {
+	if path, err = configPath(); err != nil {
+		return path, err
+	}
+
+	var bytes []byte
+	if bytes, err = json.MarshalIndent(conf, "", "  "); err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+			err = os.WriteFile(path, bytes, 0600)
+		}
+	}
+
+	return path, err
+}
+
+// resolve access token from the flag, environment variable, or config file
+func resolveAccessToken(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if token := os.Getenv(envAccessToken); token != "" {
+		return token, nil
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if conf.AccessToken == "" {
+		return "", fmt.Errorf("no access token: pass -token, set $%s, or run 'telegraph account create -save'", envAccessToken)
+	}
+
+	return conf.AccessToken, nil
+}
+
+// get a client with the resolved access token
+func clientWithToken(flagValue string) (*telegraph.Client, error) {
+	token, err := resolveAccessToken(flagValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(token), nil
+}
+
+// create a client with given access token (empty for methods which do not need one)
+func newClient(accessToken string) *telegraph.Client {
+	return telegraph.NewClient(accessToken)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		expected string // expected error message (empty for no error)
+	}{
+		{[]string{}, "no command given"},
+		{[]string{"help"}, ""},
+		{[]string{"unknown"}, "unknown command: unknown"},
+		{[]string{"account"}, "account: subcommand required"},
+		{[]string{"account", "unknown"}, "account: unknown subcommand: unknown"},
+		{[]string{"page", "unknown"}, "page: unknown subcommand: unknown"},
+		{[]string{"page", "get"}, "page get: -path is required"},
+		{[]string{"page", "get", "-unknown-flag"}, "flag provided but not defined"},
+		{[]string{"views"}, "views: -path is required"},
+		{[]string{"views", "-path", "Sample", "-year", "x"}, "invalid value"},
+	} {
+		err := run(test.args)
+		if test.expected == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %s", test.args, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%v: expected error '%s', but got: %v", test.args, test.expected, err)
+		}
+	}
+
+	if err := run([]string{"views", "-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("unexpected error for -h: %v", err)
+	}
+}
+
+func TestResolveAccessToken(t *testing.T) {
+	t.Setenv(envAccessToken, "env-token")
+
+	for flagValue, expected := range map[string]string{
+		"":           "env-token",
+		"flag-token": "flag-token",
+	} {
+		if token, err := resolveAccessToken(flagValue); err != nil || token != expected {
+			t.Errorf("unexpected access token for '%s': %s, %v", flagValue, token, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// output options shared by commands
+type outputFlags struct {
+	json bool
+}
+
+// register output flags to given flag set
+func (o *outputFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&o.json, "json", false, "print result as JSON")
+}
+
+// print given value as JSON, or as a table with given rows
+func (o *outputFlags) print(value any, header []string, rows [][]string) error {
+	return printTo(os.Stdout, o.json, value, header, rows)
+}
+
+// print to given writer
+func printTo(w io.Writer, asJSON bool, value any, header []string, rows [][]string) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(header) > 0 {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// rows of key-value pairs
+func keyValueRows(pairs ...string) (rows [][]string) {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rows = append(rows, []string{pairs[i] + ":", pairs[i+1]})
+	}
+	return rows
+}
+
+// parse flags of a subcommand, and print its usage on error
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(os.Stderr)
+	return fs.Parse(args)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestPrintTo(t *testing.T) {
+	for _, test := range []struct {
+		asJSON   bool
+		header   []string
+		rows     [][]string
+		expected string
+	}{
+		{true, nil, nil, "{\n  \"path\": \"Sample\"\n}\n"},
+		{false, nil, [][]string{{"path:", "Sample"}, {"views:", "3"}}, "path:   Sample\nviews:  3\n"},
+		{false, []string{"OLD", "NEW"}, [][]string{{"a", "bb"}}, "OLD  NEW\na    bb\n"},
+	} {
+		var b bytes.Buffer
+		if err := printTo(&b, test.asJSON, map[string]string{"path": "Sample"}, test.header, test.rows); err != nil || b.String() != test.expected {
+			t.Errorf("unexpected output: %q, expected: %q (%v)", b.String(), test.expected, err)
+		}
+	}
+}
+
+func TestKeyValueRows(t *testing.T) {
+	for _, test := range []struct {
+		pairs    []string
+		expected [][]string
+	}{
+		{nil, nil},
+		{[]string{"a", "1"}, [][]string{{"a:", "1"}}},
+		{[]string{"a", "1", "b", "2"}, [][]string{{"a:", "1"}, {"b:", "2"}}},
+		{[]string{"a", "1", "dangling"}, [][]string{{"a:", "1"}}},
+	} {
+		if rows := keyValueRows(test.pairs...); !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("unexpected rows for %v: %v", test.pairs, rows)
+		}
+	}
+}
+
+func TestSplitNonEmpty(t *testing.T) {
+	for str, expected := range map[string][]string{
+		"":            nil,
+		"a":           {"a"},
+		" a , ,b, ":   {"a", "b"},
+		"short_name,": {"short_name"},
+	} {
+		if splitted := splitNonEmpty(str, ","); !reflect.DeepEqual(splitted, expected) {
+			t.Errorf("unexpected result for '%s': %v", str, splitted)
+		}
+	}
+}
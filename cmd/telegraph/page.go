@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+// run `page` command
+func runPage(args []string) error {
+	if len(args) == 0 {
+		return errors.New("page: subcommand required (create, edit, get, list)")
+	}
+
+	switch args[0] {
+	case "create":
+		return runPageCreate(args[1:])
+	case "edit":
+		return runPageEdit(args[1:])
+	case "get":
+		return runPageGet(args[1:])
+	case "list":
+		return runPageList(args[1:])
+	}
+
+	return fmt.Errorf("page: unknown subcommand: %s", args[0])
+}
+
+// content flags shared by `page create` and `page edit`
+type contentFlags struct {
+	title         string
+	authorName    string
+	authorURL     string
+	file          string
+	format        string
+	returnContent bool
+}
+
+// register content flags to given flag set
+func (c *contentFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.title, "title", "", "title of the page (1-256 characters, required)")
+	fs.StringVar(&c.authorName, "author-name", "", "author name (0-128 characters)")
+	fs.StringVar(&c.authorURL, "author-url", "", "author url (0-512 characters)")
+	fs.StringVar(&c.file, "file", "-", "HTML or Markdown file of the content ('-' for stdin)")
+	fs.StringVar(&c.format, "format", "auto", "format of the content: auto, html, or markdown")
+	fs.BoolVar(&c.returnContent, "return-content", false, "return the content of the page")
+}
+
+// read content nodes from the file or stdin
+func (c *contentFlags) nodes() ([]telegraph.Node, error) {
+	var bytes []byte
+	var err error
+	if c.file == "-" {
+		bytes, err = io.ReadAll(os.Stdin)
+	} else {
+		bytes, err = os.ReadFile(c.file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %s", err)
+	}
+
+	format := c.format
+	if format == "auto" {
+		switch strings.ToLower(filepath.Ext(c.file)) {
+		case ".md", ".markdown":
+			format = "markdown"
+		default:
+			format = "html"
+		}
+	}
+
+	switch format {
+	case "html":
+		return telegraph.NewNodesWithHTML(string(bytes))
+	case "markdown", "md":
+		return telegraph.NewNodesWithMarkdown(string(bytes))
+	}
+
+	return nil, fmt.Errorf("unknown content format: %s", c.format)
+}
+
+// `page create`
+func runPageCreate(args []string) error {
+	fs := flag.NewFlagSet("page create", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	var content contentFlags
+	content.register(fs)
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if content.title == "" {
+		return errors.New("page create: -title is required")
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+	nodes, err := content.nodes()
+	if err != nil {
+		return err
+	}
+
+	page, err := client.CreatePage(content.title, content.authorName, content.authorURL, nodes, content.returnContent)
+	if err != nil {
+		return err
+	}
+	return out.print(page, nil, pageRows(page))
+}
+
+// `page edit`
+func runPageEdit(args []string) error {
+	fs := flag.NewFlagSet("page edit", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	path := fs.String("path", "", "path of the page (required)")
+	var content contentFlags
+	content.register(fs)
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *path == "" || content.title == "" {
+		return errors.New("page edit: -path and -title are required")
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+	nodes, err := content.nodes()
+	if err != nil {
+		return err
+	}
+
+	page, err := client.EditPage(*path, content.title, nodes, content.authorName, content.authorURL, content.returnContent)
+	if err != nil {
+		return err
+	}
+	return out.print(page, nil, pageRows(page))
+}
+
+// `page get`
+func runPageGet(args []string) error {
+	fs := flag.NewFlagSet("page get", flag.ContinueOnError)
+	path := fs.String("path", "", "path of the page (required)")
+	returnContent := fs.Bool("return-content", false, "return the content of the page")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("page get: -path is required")
+	}
+
+	// access token is not needed for fetching a page
+	page, err := newClient("").GetPage(*path, *returnContent)
+	if err != nil {
+		return err
+	}
+	return out.print(page, nil, pageRows(page))
+}
+
+// `page list`
+func runPageList(args []string) error {
+	fs := flag.NewFlagSet("page list", flag.ContinueOnError)
+	token := fs.String("token", "", "access token")
+	offset := fs.Int("offset", 0, "sequential number of the first page")
+	limit := fs.Int("limit", 50, "number of pages to be returned (0-200)")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, err := clientWithToken(*token)
+	if err != nil {
+		return err
+	}
+
+	list, err := client.GetPageList(*offset, *limit)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, page := range list.Pages {
+		rows = append(rows, []string{page.Path, page.Title, strconv.Itoa(page.Views), page.URL})
+	}
+	if err := out.print(list, []string{"PATH", "TITLE", "VIEWS", "URL"}, rows); err != nil {
+		return err
+	}
+	if !out.json {
+		fmt.Printf("(%d of %d pages)\n", len(list.Pages), list.TotalCount)
+	}
+
+	return nil
+}
+
+// table rows for a page
+func pageRows(page telegraph.Page) [][]string {
+	return keyValueRows(
+		"path", page.Path,
+		"url", page.URL,
+		"title", page.Title,
+		"description", page.Description,
+		"author name", page.AuthorName,
+		"author url", page.AuthorURL,
+		"views", strconv.Itoa(page.Views),
+	)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+func TestContentFlags(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"page.md":   "**bold**",
+		"page.html": "<b>bold</b>",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		file     string
+		format   string
+		expected string // expected HTML (empty for an error)
+	}{
+		{"page.md", "auto", "<p><b>bold</b></p>"},
+		{"page.html", "auto", "<b>bold</b>"},
+		{"page.md", "html", "**bold**"},
+		{"page.html", "unknown", ""},
+		{"missing.md", "auto", ""},
+	} {
+		c := contentFlags{file: filepath.Join(dir, test.file), format: test.format}
+		nodes, err := c.nodes()
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("%s (%s): expected an error", test.file, test.format)
+			}
+		} else if html := strings.TrimSpace(telegraph.RenderHTML(nodes)); err != nil || html != test.expected {
+			t.Errorf("%s (%s): unexpected content: %s, %v", test.file, test.format, html, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"strconv"
+)
+
+// run `views` command
+func runViews(args []string) error {
+	fs := flag.NewFlagSet("views", flag.ContinueOnError)
+	path := fs.String("path", "", "path of the page (required)")
+	year := fs.Int("year", 0, "year: 2000-2100 (required when -month is given)")
+	month := fs.Int("month", 0, "month: 1-12 (required when -day is given)")
+	day := fs.Int("day", 0, "day: 1-31 (required when -hour is given)")
+	hour := fs.Int("hour", -1, "hour: 0-24")
+	var out outputFlags
+	out.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("views: -path is required")
+	}
+
+	views, err := newClient("").GetViews(*path, *year, *month, *day, *hour)
+	if err != nil {
+		return err
+	}
+	return out.print(views, nil, keyValueRows("path", *path, "views", strconv.Itoa(views.Views)))
+}
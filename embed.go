@@ -0,0 +1,118 @@
+package telegraph
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Embedded contents
+//
+// Telegraph renders embedded contents with iframes whose src are like "/embed/youtube?url=...".
+
+// EmbedType is a type of embedded contents.
+type EmbedType string
+
+// EmbedType constants
+const (
+	EmbedYouTube  EmbedType = "youtube"
+	EmbedVimeo    EmbedType = "vimeo"
+	EmbedTwitter  EmbedType = "twitter"
+	EmbedTelegram EmbedType = "telegram"
+)
+
+// regular expressions of urls for each embed type
+var embedURLRegexes = map[EmbedType]*regexp.Regexp{
+	EmbedYouTube:  regexp.MustCompile(`^https?://(?:(?:www\.|m\.)?youtube\.com/(?:watch\?(?:.*&)?v=|shorts/|embed/)|youtu\.be/)[\w-]+`),
+	EmbedVimeo:    regexp.MustCompile(`^https?://(?:www\.|player\.)?vimeo\.com/(?:video/)?\d+`),
+	EmbedTwitter:  regexp.MustCompile(`^https?://(?:www\.|mobile\.)?(?:twitter|x)\.com/\w+/status/\d+`),
+	EmbedTelegram: regexp.MustCompile(`^https?://(?:t\.me|telegram\.me)/\w+/\d+`),
+}
+
+// NewEmbed creates a new node of embedded content with given url, wrapped in a figure with an optional caption.
+func NewEmbed(embedType EmbedType, contentURL, caption string) Node {
+	children := []Node{NodeElement{
+		Tag:   "iframe",
+		Attrs: map[string]string{"src": "/embed/" + url.PathEscape(string(embedType)) + "?url=" + url.QueryEscape(contentURL)},
+	}}
+	if caption != "" {
+		children = append(children, NodeElement{Tag: "figcaption", Children: []Node{caption}})
+	}
+
+	return NodeElement{Tag: "figure", Children: children}
+}
+
+// NewYouTubeEmbed creates a new node of embedded YouTube video.
+func NewYouTubeEmbed(videoURL, caption string) Node {
+	return NewEmbed(EmbedYouTube, videoURL, caption)
+}
+
+// NewVimeoEmbed creates a new node of embedded Vimeo video.
+func NewVimeoEmbed(videoURL, caption string) Node {
+	return NewEmbed(EmbedVimeo, videoURL, caption)
+}
+
+// NewTwitterEmbed creates a new node of embedded tweet (Twitter/X post).
+func NewTwitterEmbed(tweetURL, caption string) Node {
+	return NewEmbed(EmbedTwitter, tweetURL, caption)
+}
+
+// NewTelegramEmbed creates a new node of embedded Telegram post (eg. "https://t.me/channel/123").
+func NewTelegramEmbed(postURL, caption string) Node {
+	return NewEmbed(EmbedTelegram, postURL, caption)
+}
+
+// DetectEmbed returns the embed type of given url, if it can be embedded.
+func DetectEmbed(contentURL string) (embedType EmbedType, ok bool) {
+	for embedType, regex := range embedURLRegexes {
+		if regex.MatchString(contentURL) {
+			return embedType, true
+		}
+	}
+
+	return "", false
+}
+
+// replace top-level paragraphs (or texts) which only have an embeddable url with embeds
+func detectEmbeds(nodes []Node) []Node {
+	for i, node := range nodes {
+		var link string
+		switch n := node.(type) {
+		case string:
+			link = strings.TrimSpace(n)
+		case NodeElement:
+			if n.Tag == "p" {
+				link = bareLink(n.Children)
+			}
+		}
+
+		if embedType, ok := DetectEmbed(link); ok && !strings.ContainsAny(link, " \t\n") {
+			nodes[i] = NewEmbed(embedType, link, "")
+		}
+	}
+
+	return nodes
+}
+
+// url of a bare link (a url text, or a link whose text is its url) in given nodes
+func bareLink(nodes []Node) (link string) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case string:
+			if strings.TrimSpace(n) == "" {
+				continue
+			}
+			if link != "" {
+				return ""
+			}
+			link = strings.TrimSpace(n)
+		case NodeElement:
+			if link != "" || n.Tag != "a" || strings.TrimSpace(NodesText(n.Children)) != n.Attrs["href"] {
+				return ""
+			}
+			link = n.Attrs["href"]
+		}
+	}
+
+	return link
+}
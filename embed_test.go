@@ -0,0 +1,32 @@
+package telegraph
+
+import "testing"
+
+func TestEmbeds(t *testing.T) {
+	expected := `<figure><iframe src="/embed/vimeo?url=https%3A%2F%2Fvimeo.com%2F123"></iframe><figcaption>video</figcaption></figure>`
+	if html := RenderHTML([]Node{NewVimeoEmbed("https://vimeo.com/123", "video")}); html != expected {
+		t.Errorf("unexpected embed: %s", html)
+	}
+
+	for url, expected := range map[string]EmbedType{
+		"https://www.youtube.com/watch?v=abc": EmbedYouTube,
+		"https://youtu.be/abc":                EmbedYouTube,
+		"https://x.com/user/status/123":       EmbedTwitter,
+		"https://t.me/channel/123":            EmbedTelegram,
+	} {
+		if embedType, ok := DetectEmbed(url); !ok || embedType != expected {
+			t.Errorf("unexpected embed type of %s: %s", url, embedType)
+		}
+	}
+
+	// bare links on their own lines are converted to embeds
+	nodes, _ := NewNodesWithMarkdown("intro\n\nhttps://youtu.be/abc\n\n<https://twitter.com/user/status/1>\n\nsee https://youtu.be/abc")
+	expected = `<p>intro</p>
+<figure><iframe src="/embed/youtube?url=https%3A%2F%2Fyoutu.be%2Fabc"></iframe></figure>
+<figure><iframe src="/embed/twitter?url=https%3A%2F%2Ftwitter.com%2Fuser%2Fstatus%2F1"></iframe></figure>
+<p>see https://youtu.be/abc</p>
+`
+	if html := RenderHTML(nodes); html != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", html, expected)
+	}
+}
@@ -0,0 +1,342 @@
+package telegraph
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EPUB export
+//
+// Pages (with contents fetched via GetPage) are exported as an EPUB 3 book,
+// each page being a chapter:
+//
+//	file, _ := os.Create("series.epub")
+//	err := telegraph.WriteEPUB(file, pages, telegraph.EPUBOptions{Title: "My Series"})
+
+// EPUBOptions is the options for exporting an EPUB file.
+type EPUBOptions struct {
+	Title      string    // title of the book (default: title of the first page)
+	Author     string    // author of the book (default: author name of the first page, or of Account)
+	Account    Account   // account of pages (optional)
+	Language   string    // language code of the book (default: "en")
+	Identifier string    // unique identifier of the book (default: generated from urls of pages)
+	Modified   time.Time // last modified time of the book (default: now)
+
+	// ImageDir is a local directory of images to be bundled (optional).
+	//
+	// Images hosted on Telegraph whose file names (eg. "abc.jpg" of "/file/abc.jpg") exist in this directory
+	// are bundled in the book, and others are replaced with links to their remote urls (EPUB 3 does not allow remote images).
+	ImageDir string
+}
+
+// an image bundled in the book
+type epubImage struct {
+	name      string // file name in the book
+	localPath string
+}
+
+// a chapter of the book
+type epubChapter struct {
+	file     string
+	title    string
+	body     string
+	headings []epubHeading
+}
+
+// a heading in a chapter
+type epubHeading struct {
+	id    string
+	level int // 3 or 4
+	text  string
+}
+
+// WriteEPUB writes given pages as an EPUB 3 file to `w`.
+//
+// Links between given pages are rewritten to links between chapters,
+// and h3/h4 headings of pages are listed in the navigation document.
+func WriteEPUB(w io.Writer, pages []Page, options EPUBOptions) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("no pages to export")
+	}
+
+	if options.Title == "" {
+		options.Title = pages[0].Title
+	}
+	if options.Author == "" {
+		if options.Author = pages[0].AuthorName; options.Author == "" {
+			options.Author = options.Account.AuthorName
+		}
+	}
+	if options.Language == "" {
+		options.Language = "en"
+	}
+	if options.Identifier == "" {
+		hash := sha256.New()
+		for _, page := range pages {
+			io.WriteString(hash, page.URL+"\n")
+		}
+		sum := hash.Sum(nil)
+		options.Identifier = fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+	}
+	if options.Modified.IsZero() {
+		options.Modified = time.Now()
+	}
+
+	// files of chapters
+	files := map[string]string{} // page path => chapter file
+	for i, page := range pages {
+		files[page.Path] = fmt.Sprintf("chapter%03d.xhtml", i+1)
+	}
+
+	images := map[string]epubImage{} // local path => image
+	chapters := []epubChapter{}
+	for _, page := range pages {
+		chapter := epubChapter{file: files[page.Path], title: page.Title}
+		content := chapter.convertNodes(page.Content, files, images, options.ImageDir)
+		chapter.body = RenderXHTML(content)
+		chapters = append(chapters, chapter)
+	}
+
+	zw := zip.NewWriter(w)
+
+	// mimetype should be the first one, and not compressed
+	if mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store}); err != nil {
+		return err
+	} else if _, err := io.WriteString(mw, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	documents := map[string]string{
+		"META-INF/container.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`,
+		"OEBPS/content.opf": epubPackage(options, chapters, images),
+		"OEBPS/nav.xhtml":   epubNav(options, chapters),
+	}
+	for _, chapter := range chapters {
+		documents["OEBPS/"+chapter.file] = epubXHTML(options.Language, chapter.title, "<h1>"+html.EscapeString(chapter.title)+"</h1>\n"+chapter.body)
+	}
+	for _, name := range sortedKeys(documents) {
+		if fw, err := zw.Create(name); err != nil {
+			return err
+		} else if _, err := io.WriteString(fw, documents[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, localPath := range sortedKeys(images) {
+		image := images[localPath]
+
+		bytes, err := os.ReadFile(image.localPath)
+		if err != nil {
+			return fmt.Errorf("failed to read image '%s': %s", image.localPath, err)
+		}
+		if fw, err := zw.Create("OEBPS/images/" + image.name); err != nil {
+			return err
+		} else if _, err := fw.Write(bytes); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// convert nodes for a chapter: rewrite links and images, replace embedded media with links, and set ids of headings
+func (c *epubChapter) convertNodes(nodes []Node, files map[string]string, images map[string]epubImage, imageDir string) []Node {
+	converted := []Node{}
+
+	for _, node := range nodes {
+		switch n := normalizeNode(node).(type) {
+		case string:
+			converted = append(converted, n)
+		case NodeElement:
+			attrs := map[string]string{}
+			for key, value := range n.Attrs {
+				attrs[key] = value
+			}
+
+			switch n.Tag {
+			case "a":
+				if href, exists := attrs["href"]; exists {
+					if file, exists := files[strings.TrimPrefix(strings.TrimPrefix(href, pageBaseURL), "/")]; exists {
+						attrs["href"] = file
+					} else {
+						attrs["href"] = absoluteURL(href)
+					}
+				}
+			case "img":
+				src := attrs["src"]
+				if localPath := localImagePath(src, imageDir); localPath != "" {
+					image, exists := images[localPath]
+					if !exists {
+						image = epubImage{name: filepath.Base(localPath), localPath: localPath}
+						images[localPath] = image
+					}
+					attrs["src"] = "images/" + image.name
+				} else { // remote images are not allowed in EPUB 3, so replace it with a link
+					text := attrs["alt"]
+					if text == "" {
+						text = absoluteURL(src)
+					}
+					converted = append(converted, NodeElement{Tag: "a", Attrs: map[string]string{"href": absoluteURL(src)}, Children: []Node{text}})
+					continue
+				}
+				if _, exists := attrs["alt"]; !exists {
+					attrs["alt"] = ""
+				}
+			case "iframe", "video":
+				src := mediaURL(attrs["src"])
+				converted = append(converted, NodeElement{Tag: "a", Attrs: map[string]string{"href": src}, Children: []Node{src}})
+				continue
+			case "h3", "h4":
+				heading := epubHeading{
+					id:    fmt.Sprintf("h%d", len(c.headings)+1),
+					level: 3,
+					text:  NodesText(n.Children),
+				}
+				if n.Tag == "h4" {
+					heading.level = 4
+				}
+				attrs["id"] = heading.id
+				c.headings = append(c.headings, heading)
+			}
+
+			converted = append(converted, NodeElement{
+				Tag:      n.Tag,
+				Attrs:    attrs,
+				Children: c.convertNodes(n.Children, files, images, imageDir),
+			})
+		}
+	}
+
+	return converted
+}
+
+// generate the package document
+func epubPackage(options EPUBOptions, chapters []epubChapter, images map[string]epubImage) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+`)
+	fmt.Fprintf(&b, "    <dc:identifier id=\"bookid\">%s</dc:identifier>\n", html.EscapeString(options.Identifier))
+	fmt.Fprintf(&b, "    <dc:title>%s</dc:title>\n", html.EscapeString(options.Title))
+	fmt.Fprintf(&b, "    <dc:language>%s</dc:language>\n", html.EscapeString(options.Language))
+	if options.Author != "" {
+		fmt.Fprintf(&b, "    <dc:creator>%s</dc:creator>\n", html.EscapeString(options.Author))
+	}
+	fmt.Fprintf(&b, "    <meta property=\"dcterms:modified\">%s</meta>\n", options.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	b.WriteString("  </metadata>\n  <manifest>\n")
+	b.WriteString("    <item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	for i, chapter := range chapters {
+		fmt.Fprintf(&b, "    <item id=\"chapter%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", i+1, chapter.file)
+	}
+	for i, localPath := range sortedKeys(images) {
+		name := images[localPath].name
+		mediaType := mime.TypeByExtension(path.Ext(name))
+		if mediaType == "" {
+			mediaType = "application/octet-stream"
+		}
+		fmt.Fprintf(&b, "    <item id=\"image%d\" href=\"images/%s\" media-type=\"%s\"/>\n", i+1, html.EscapeString(name), mediaType)
+	}
+	b.WriteString("  </manifest>\n  <spine>\n")
+	for i := range chapters {
+		fmt.Fprintf(&b, "    <itemref idref=\"chapter%d\"/>\n", i+1)
+	}
+	b.WriteString("  </spine>\n</package>\n")
+
+	return b.String()
+}
+
+// generate the navigation document
+func epubNav(options EPUBOptions, chapters []epubChapter) string {
+	var b strings.Builder
+
+	b.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<ol>\n", html.EscapeString(options.Title))
+	for _, chapter := range chapters {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a>", chapter.file, html.EscapeString(chapter.title))
+
+		// h3 headings, and h4 headings nested in them
+		depth := 0
+		for _, heading := range chapter.headings {
+			level := heading.level - 2 // 1 or 2
+			if level > depth+1 {
+				level = depth + 1
+			}
+			switch {
+			case level > depth:
+				b.WriteString("\n<ol>")
+			case level == depth:
+				b.WriteString("</li>")
+			default:
+				b.WriteString("</li></ol></li>")
+			}
+			depth = level
+			fmt.Fprintf(&b, "<li><a href=\"%s#%s\">%s</a>", chapter.file, heading.id, html.EscapeString(heading.text))
+		}
+		for ; depth > 0; depth-- {
+			b.WriteString("</li></ol>")
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ol>\n</nav>")
+
+	return epubXHTML(options.Language, options.Title, b.String())
+}
+
+// generate a XHTML content document
+func epubXHTML(language, title, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%[1]s" xml:lang="%[1]s">
+<head>
+<meta charset="UTF-8"/>
+<title>%[2]s</title>
+</head>
+<body>
+%[3]s
+</body>
+</html>
+`, html.EscapeString(language), html.EscapeString(title), body)
+}
+
+// local path of an image hosted on Telegraph (eg. "/file/abc.jpg" => "{dir}/abc.jpg"), or empty if it does not exist
+func localImagePath(src, dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	u, err := url.Parse(src)
+	if err != nil || (u.Host != "" && u.Scheme+"://"+u.Host != pageBaseURL) || !strings.HasPrefix(u.Path, "/file/") {
+		return ""
+	}
+
+	// file names in the directory are unique, so are the ones in the book
+	if localPath := filepath.Join(dir, path.Base(u.Path)); fileExists(localPath) {
+		return localPath
+	}
+
+	return ""
+}
+
+// check if a regular file exists at given path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
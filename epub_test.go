@@ -0,0 +1,78 @@
+package telegraph
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteEPUB(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "local.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pages := []Page{
+		{Path: "Part-1-01-01", URL: "https://telegra.ph/Part-1-01-01", Title: "Part 1", AuthorName: "Author", Content: []Node{
+			NewNodeWithElement("h3", nil, []Node{"Section"}),
+			NewNodeWithElement("h4", nil, []Node{"Subsection"}),
+			NewNodeWithElement("p", nil, []Node{NewNodeWithElement("a", map[string]string{"href": "/Part-2-01-02"}, []Node{"next"})}),
+			NewNodeWithElement("img", map[string]string{"src": "/file/local.png"}, nil),
+		}},
+		{Path: "Part-2-01-02", URL: "https://telegra.ph/Part-2-01-02", Title: "Part 2", Content: []Node{
+			NewNodeWithElement("img", map[string]string{"src": "/file/remote.png"}, nil),
+			NewNodeWithElement("img", map[string]string{"src": "https://telegra.ph/file/local.png?size=large"}, nil),         // same image
+			NewNodeWithElement("img", map[string]string{"src": "https://example.com/file/local.png", "alt": "foreign"}, nil), // same file name on another host
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEPUB(&buf, pages, EPUBOptions{Title: "Series", ImageDir: dir}); err != nil {
+		t.Fatalf("failed to write epub: %s", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read epub: %s", err)
+	}
+	if first := zr.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+		t.Errorf("mimetype should be the first stored file: %#+v", first.FileHeader)
+	}
+
+	images := 0
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, "OEBPS/images/") {
+			images++
+		}
+	}
+	if images != 1 {
+		t.Errorf("same image should be bundled once, but %d images are bundled", images)
+	}
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, _ := f.Open()
+		b, _ := io.ReadAll(r)
+		files[f.Name] = string(b)
+	}
+
+	for name, expected := range map[string][]string{
+		"OEBPS/content.opf": {`<dc:title>Series</dc:title>`, `<dc:creator>Author</dc:creator>`, `<item id="image1" href="images/local.png" media-type="image/png"/>`},
+		"OEBPS/nav.xhtml": {`<li><a href="chapter001.xhtml">Part 1</a>
+<ol><li><a href="chapter001.xhtml#h1">Section</a>
+<ol><li><a href="chapter001.xhtml#h2">Subsection</a></li></ol></li></ol></li>`},
+		"OEBPS/chapter001.xhtml": {`<h3 id="h1">Section</h3>`, `<a href="chapter002.xhtml">next</a>`, `<img alt="" src="images/local.png"/>`},
+		"OEBPS/chapter002.xhtml": {`<a href="https://telegra.ph/file/remote.png">https://telegra.ph/file/remote.png</a>`, `<img alt="" src="images/local.png"/>`, `<a href="https://example.com/file/local.png">foreign</a>`},
+		"OEBPS/images/local.png": {"png"},
+	} {
+		for _, e := range expected {
+			if !strings.Contains(files[name], e) {
+				t.Errorf("%s does not contain %s:\n%s", name, e, files[name])
+			}
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package telegraph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// RSS and Atom feeds
+//
+// Feed is generated from pages of an account:
+//
+//	feed, _ := telegraph.NewFeed(client, telegraph.FeedOptions{FetchContent: true})
+//	rss, _ := feed.RSS()
+//	atom, _ := feed.Atom()
+//
+// Telegraph does not provide dates of pages, so they are guessed from page paths
+// (eg. "Sample-Page-12-15" => December 15 of the current or last year) unless FeedOptions.Published is given.
+
+// FeedOptions is the options for generating a Feed.
+type FeedOptions struct {
+	Title       string // title of the feed (default: author name or short name of the account)
+	Link        string // link of the feed (default: author url of the account, or the url of the newest page)
+	Description string // description of the feed (default: same as the title)
+
+	Limit        int  // maximum number of items (0 = all pages)
+	FetchContent bool // fetch contents of pages with GetPage, for full HTML contents of items
+
+	// Published returns the published time of a page (default: guessed from its path with PageDate)
+	Published func(page Page) time.Time
+
+	// Now returns the current time (default: time.Now)
+	Now func() time.Time
+}
+
+// Feed is a feed of pages.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	AuthorName  string
+	AuthorURL   string
+	Updated     time.Time
+	Items       []FeedItem
+}
+
+// FeedItem is an item of Feed.
+type FeedItem struct {
+	Page      Page // (with content if fetched)
+	Published time.Time
+}
+
+// NewFeed creates a new Feed with pages of the account, the newest first.
+func NewFeed(client API, options FeedOptions) (feed Feed, err error) {
+	now := time.Now
+	if options.Now != nil {
+		now = options.Now
+	}
+	published := options.Published
+	if published == nil {
+		published = func(page Page) time.Time {
+			date, _ := PageDate(page.Path, now())
+			return date
+		}
+	}
+
+	var account Account
+	if account, err = client.GetAccountInfo([]string{"short_name", "author_name", "author_url"}); err != nil {
+		return feed, fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	feed = Feed{
+		Title:       options.Title,
+		Link:        options.Link,
+		Description: options.Description,
+		AuthorName:  account.AuthorName,
+		AuthorURL:   account.AuthorURL,
+	}
+	if feed.Title == "" {
+		if feed.Title = account.AuthorName; feed.Title == "" {
+			feed.Title = account.ShortName
+		}
+	}
+	if feed.Link == "" {
+		feed.Link = account.AuthorURL
+	}
+	if feed.Description == "" {
+		feed.Description = feed.Title
+	}
+
+	var pages []Page
+	if pages, err = ListAllPages(client); err != nil {
+		return feed, fmt.Errorf("failed to list pages: %w", err)
+	}
+	if options.Limit > 0 && len(pages) > options.Limit {
+		pages = pages[:options.Limit]
+	}
+
+	for _, page := range pages {
+		if options.FetchContent {
+			var fetched Page
+			if fetched, err = client.GetPage(page.Path, true); err != nil {
+				return feed, fmt.Errorf("failed to get page '%s': %w", page.Path, err)
+			}
+			page = fetched
+		}
+
+		item := FeedItem{Page: page, Published: published(page)}
+		if item.Published.After(feed.Updated) {
+			feed.Updated = item.Published
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = now()
+	}
+	feed.Link = feed.link()
+
+	return feed, nil
+}
+
+// regular expression for dates in page paths (eg. "Sample-Page-12-15", "Sample-Page-12-15-2")
+var pageDateRegex = regexp.MustCompile(`-(\d{2})-(\d{2})(?:-\d+)?$`)
+
+// PageDate guesses the date of a page from its path,
+// which has the month and day (but not the year) of its creation.
+//
+// The year is assumed to be that of `now`, or the last year if the date is after `now`.
+func PageDate(path string, now time.Time) (date time.Time, ok bool) {
+	matches := pageDateRegex.FindStringSubmatch(path)
+	if matches == nil {
+		return date, false
+	}
+
+	month, _ := strconv.Atoi(matches[1])
+	day, _ := strconv.Atoi(matches[2])
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return date, false
+	}
+
+	date = time.Date(now.Year(), time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.After(now) {
+		date = date.AddDate(-1, 0, 0)
+	}
+
+	return date, true
+}
+
+////////////////
+// RSS 2.0
+
+type rss struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Content     string  `xml:"content:encoded,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS returns the feed as a RSS 2.0 document.
+func (f Feed) RSS() ([]byte, error) {
+	doc := rss{
+		Version:   "2.0",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.link(),
+			Description:   f.Description,
+			LastBuildDate: f.Updated.Format(time.RFC1123Z),
+			Generator:     "telegraph-go",
+		},
+	}
+
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Page.Title,
+			Link:        item.Page.URL,
+			GUID:        rssGUID{IsPermaLink: true, Value: item.Page.URL},
+			Description: item.Page.Description,
+			Creator:     f.author(item.Page),
+			Content:     feedContent(item.Page.Content),
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = item.Published.Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+////////////////
+// Atom 1.0
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Link      *atomLink   `xml:"link,omitempty"`
+	Author    atomAuthor  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Link      atomLink    `xml:"link"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   *atomText   `xml:"summary,omitempty"`
+	Content   *atomText   `xml:"content,omitempty"`
+}
+
+// Atom returns the feed as an Atom 1.0 document.
+func (f Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		ID:        f.link(),
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Updated:   f.Updated.Format(time.RFC3339),
+		Author:    atomAuthor{Name: f.AuthorName, URI: f.AuthorURL},
+		Generator: "telegraph-go",
+	}
+	if doc.Author.Name == "" { // author name is required
+		doc.Author.Name = f.Title
+	}
+	doc.Link = &atomLink{Rel: "alternate", Href: f.link()}
+
+	for _, item := range f.Items {
+		updated := item.Published
+		if updated.IsZero() {
+			updated = f.Updated
+		}
+
+		entry := atomEntry{
+			ID:      item.Page.URL,
+			Title:   item.Page.Title,
+			Link:    atomLink{Rel: "alternate", Href: item.Page.URL},
+			Updated: updated.Format(time.RFC3339),
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.Format(time.RFC3339)
+		}
+		if item.Page.AuthorName != "" && item.Page.AuthorName != f.AuthorName {
+			entry.Author = &atomAuthor{Name: item.Page.AuthorName, URI: item.Page.AuthorURL}
+		}
+		if item.Page.Description != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Page.Description}
+		}
+		if len(item.Page.Content) > 0 {
+			entry.Content = &atomText{Type: "html", Value: feedContent(item.Page.Content)}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+// link of the feed (required in both RSS and Atom):
+// Link, or the url of the first item, or the base url of Telegraph
+func (f Feed) link() string {
+	if f.Link != "" {
+		return f.Link
+	}
+	if len(f.Items) > 0 && f.Items[0].Page.URL != "" {
+		return f.Items[0].Page.URL
+	}
+
+	return pageBaseURL
+}
+
+// render content of a page for feed readers, with relative urls (eg. "/file/abc.jpg") made absolute
+func feedContent(nodes []Node) string {
+	content, _ := TransformNodes(nodes, nil, absoluteURL)
+
+	return RenderHTML(content)
+}
+
+// author name of a page
+func (f Feed) author(page Page) string {
+	if page.AuthorName != "" {
+		return page.AuthorName
+	}
+
+	return f.AuthorName
+}
+
+// marshal given value as an indented XML document
+func marshalXML(v any) ([]byte, error) {
+	bytes, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode xml: %s", err)
+	}
+
+	return append([]byte(xml.Header), bytes...), nil
+}
@@ -0,0 +1,65 @@
+package telegraph_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	telegraph "github.com/meinside/telegraph-go"
+	"github.com/meinside/telegraph-go/telegraphtest"
+)
+
+func TestFeed(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	fake := telegraphtest.NewFake()
+	fake.SetAccount(telegraph.Account{ShortName: "blog", AuthorName: "Author", AuthorURL: "https://example.com"})
+	fake.AddPage(telegraph.Page{Path: "Old-Post-12-31", Title: "Old Post", Content: []telegraph.Node{"old"}})
+	fake.AddPage(telegraph.Page{Path: "New-Post-01-05", Title: "New <Post>", Description: "new", Content: []telegraph.Node{telegraph.NewNodeWithElement("p", nil, []telegraph.Node{"new"}), telegraph.Img("/file/abc.jpg")}})
+
+	feed, err := telegraph.NewFeed(fake, telegraph.FeedOptions{FetchContent: true, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("failed to create feed: %s", err)
+	}
+	if len(feed.Items) != 2 || feed.Items[0].Page.Title != "New <Post>" || feed.Items[1].Published.Year() != 2023 {
+		t.Fatalf("unexpected items: %#+v", feed.Items)
+	}
+
+	rss, err := feed.RSS()
+	if err != nil {
+		t.Fatalf("failed to generate rss: %s", err)
+	}
+	for _, expected := range []string{
+		`<rss version="2.0"`,
+		`<title>New &lt;Post&gt;</title>`,
+		`<dc:creator>Author</dc:creator>`,
+		`<pubDate>Fri, 05 Jan 2024 00:00:00 +0000</pubDate>`,
+		`<content:encoded>&lt;p&gt;new&lt;/p&gt;&lt;img src=&#34;https://telegra.ph/file/abc.jpg&#34;&gt;</content:encoded>`,
+	} {
+		if !strings.Contains(string(rss), expected) {
+			t.Errorf("rss does not contain %s:\n%s", expected, rss)
+		}
+	}
+
+	atom, err := feed.Atom()
+	if err != nil {
+		t.Fatalf("failed to generate atom: %s", err)
+	}
+	for _, expected := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<updated>2024-01-05T00:00:00Z</updated>`,
+		`<id>https://telegra.ph/Old-Post-12-31</id>`,
+		`<content type="html">old</content>`,
+	} {
+		if !strings.Contains(string(atom), expected) {
+			t.Errorf("atom does not contain %s:\n%s", expected, atom)
+		}
+	}
+
+	// link is required
+	rss, _ = telegraph.Feed{Title: "no link"}.RSS()
+	atom, _ = telegraph.Feed{Title: "no link"}.Atom()
+	if !strings.Contains(string(rss), `<link>https://telegra.ph</link>`) || !strings.Contains(string(atom), `<id>https://telegra.ph</id>`) {
+		t.Errorf("feeds without link should have the default one:\n%s\n%s", rss, atom)
+	}
+}
@@ -0,0 +1,80 @@
+package telegraph
+
+import (
+	"errors"
+	"time"
+)
+
+// Request hooks
+
+// ErrorClass is a classification of request errors.
+type ErrorClass string
+
+// ErrorClass constants
+const (
+	ErrorClassNone      ErrorClass = "none"       // no error
+	ErrorClassNetwork   ErrorClass = "network"    // failed to send a request or receive its response
+	ErrorClassDecode    ErrorClass = "decode"     // failed to decode a response
+	ErrorClassAPI       ErrorClass = "api"        // the API responded with an error
+	ErrorClassFloodWait ErrorClass = "flood_wait" // the API responded with FLOOD_WAIT_X error
+)
+
+// RequestInfo is the information of a request, passed to RequestHooks.
+//
+// Fields after `Retries` are filled only for RequestHook.AfterRequest.
+type RequestInfo struct {
+	Method  string            // name of the API method (eg. "getPage")
+	Path    string            // path of the page (only for methods which need it)
+	Params  map[string]string // params with secret values redacted, and long values truncated
+	Retries int               // number of retries on FLOOD_WAIT_X errors before this request
+
+	Duration     time.Duration
+	Status       int // HTTP status code (0 if no response)
+	ResponseSize int // size of the response body in bytes
+	Err          error
+	ErrorClass   ErrorClass
+}
+
+// RequestHook is an interface for hooks invoked around every request (including retries).
+//
+// Hooks are called synchronously, so they should return quickly.
+type RequestHook interface {
+	BeforeRequest(info RequestInfo)
+	AfterRequest(info RequestInfo)
+}
+
+// WithRequestHooks appends RequestHooks to the client.
+func WithRequestHooks(hooks ...RequestHook) ClientOption {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
+
+// request hooks of the client
+func (c *Client) requestHooks() []RequestHook {
+	if c == nil {
+		return nil
+	}
+
+	return c.hooks
+}
+
+// classify given error
+func classifyError(err error) ErrorClass {
+	var floodWait *FloodWaitError
+	var apiError *APIError
+	var decode *decodeError
+
+	switch {
+	case err == nil:
+		return ErrorClassNone
+	case errors.As(err, &floodWait):
+		return ErrorClassFloodWait
+	case errors.As(err, &apiError):
+		return ErrorClassAPI
+	case errors.As(err, &decode):
+		return ErrorClassDecode
+	}
+
+	return ErrorClassNetwork
+}
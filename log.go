@@ -0,0 +1,199 @@
+package telegraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Logging
+//
+// All logs go through a handler which redacts access tokens and auth urls,
+// both from attributes with secret keys and from string values which contain them.
+
+// keys of secret values
+var secretKeys = []string{"access_token", "auth_url"}
+
+// regular expression for secret values in strings (form-encoded, JSON, Go syntax, ...)
+var secretValueRegex = regexp.MustCompile(`(?i)("?(?:access_token|auth_url|AccessToken|AuthURL)"?\s*[:=]\s*"?)([^"&\s,}]+)`)
+
+// replacement of redacted values
+const redacted = "[REDACTED]"
+
+// maximum length of a logged param value
+const maxLoggedParamLength = 256
+
+// WithLogger sets a structured logger for the client.
+//
+// Requests are logged at debug level, and failures at warn/error level.
+// Access tokens and auth urls are always redacted.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.log = newRedactingLogger(logger)
+	}
+}
+
+// logger of the client
+//
+// (if not set, slog.Default() is used, or a debug-level logger when Verbose is true)
+func (c *Client) logger() *slog.Logger {
+	if c != nil && c.log != nil {
+		return c.log
+	}
+
+	return defaultLogger()
+}
+
+// default logger for clients without their own one
+func defaultLogger() *slog.Logger {
+	if Verbose {
+		return verboseLogger()
+	}
+
+	// rebuilt only when slog.Default() is changed (eg. with slog.SetDefault)
+	base := slog.Default()
+	if wrapped := redactedDefault.Load(); wrapped != nil && wrapped.base == base {
+		return wrapped.logger
+	}
+	wrapped := &redactedLogger{base: base, logger: newRedactingLogger(base)}
+	redactedDefault.Store(wrapped)
+
+	return wrapped.logger
+}
+
+// debug-level logger used when Verbose is true
+var verboseLogger = sync.OnceValue(func() *slog.Logger {
+	return newRedactingLogger(slog.New(slog.NewTextHandler(log.Writer(), &slog.HandlerOptions{Level: slog.LevelDebug})))
+})
+
+// redacting logger of slog.Default()
+var redactedDefault atomic.Pointer[redactedLogger]
+
+// a logger wrapped with a redacting handler
+type redactedLogger struct {
+	base   *slog.Logger
+	logger *slog.Logger
+}
+
+// wrap given logger with a redacting handler
+func newRedactingLogger(logger *slog.Logger) *slog.Logger {
+	if _, ok := logger.Handler().(redactingHandler); ok {
+		return logger
+	}
+
+	return slog.New(redactingHandler{handler: logger.Handler()})
+}
+
+// slog.Handler which redacts secret values
+type redactingHandler struct {
+	handler slog.Handler
+}
+
+// Enabled reports whether the wrapped handler handles records at given level.
+func (h redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle redacts secret values in given record and passes it to the wrapped handler.
+func (h redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	r := slog.NewRecord(record.Time, record.Level, redactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		r.AddAttrs(redactAttr(attr))
+		return true
+	})
+
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a new handler with redacted attributes.
+func (h redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = redactAttr(attr)
+	}
+
+	return redactingHandler{handler: h.handler.WithAttrs(redactedAttrs)}
+}
+
+// WithGroup returns a new handler with given group.
+func (h redactingHandler) WithGroup(name string) slog.Handler {
+	return redactingHandler{handler: h.handler.WithGroup(name)}
+}
+
+// redact secret values in given attribute
+func redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if slices.Contains(secretKeys, strings.ToLower(attr.Key)) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(redactString(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redactedGroup := make([]slog.Attr, len(group))
+		for i, a := range group {
+			redactedGroup[i] = redactAttr(a)
+		}
+		attr.Value = slog.GroupValue(redactedGroup...)
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			attr.Value = slog.StringValue(redactString(err.Error()))
+		} else {
+			attr.Value = slog.StringValue(redactString(fmt.Sprintf("%+v", attr.Value.Any())))
+		}
+	}
+
+	return attr
+}
+
+// redact secret values in given string
+func redactString(str string) string {
+	return secretValueRegex.ReplaceAllString(str, "${1}"+redacted)
+}
+
+// attribute for logging request params
+func paramsAttr(params map[string]string) slog.Attr {
+	attrs := []any{}
+	for _, key := range sortedKeys(params) {
+		attrs = append(attrs, slog.String(key, params[key]))
+	}
+
+	return slog.Group("params", attrs...)
+}
+
+// convert request params to strings with secret values redacted, and long values truncated
+func redactedParams(params map[string]any) map[string]string {
+	redactedParams := map[string]string{}
+	for key, value := range params {
+		if slices.Contains(secretKeys, key) {
+			redactedParams[key] = redacted
+			continue
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			if bytes, err := json.Marshal(value); err == nil {
+				str = string(bytes)
+			} else {
+				str = fmt.Sprintf("%v", value)
+			}
+		}
+		if len(str) > maxLoggedParamLength {
+			str = fmt.Sprintf("%s... (%d bytes)", str[:maxLoggedParamLength], len(str))
+		}
+
+		redactedParams[key] = redactString(str)
+	}
+
+	return redactedParams
+}
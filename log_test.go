@@ -0,0 +1,66 @@
+package telegraph
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoggerRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	transport := &cannedTransport{responses: []string{`not a json: {"access_token":"secret-new-token","auth_url":"https://edit.telegra.ph/auth/secret"}`}}
+	client := NewClient("secret-token", WithLogger(logger), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if _, err := client.RevokeAccessToken(); err == nil {
+		t.Fatalf("should fail with malformed response")
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"secret-token", "secret-new-token", "auth/secret"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("secret '%s' was not redacted: %s", secret, logs)
+		}
+	}
+	for _, field := range []string{`"method":"revokeAccessToken"`, `"duration":`, `"status":200`, `"retries":0`, `"level":"ERROR"`} {
+		if !strings.Contains(logs, field) {
+			t.Errorf("field %s was not logged: %s", field, logs)
+		}
+	}
+}
+
+func TestRedactString(t *testing.T) {
+	for str, expected := range map[string]string{
+		"access_token=abc&title=x":           "access_token=[REDACTED]&title=x",
+		`{"access_token":"abc","ok":true}`:   `{"access_token":"[REDACTED]","ok":true}`,
+		`{AccessToken:abc AuthURL:https://}`: `{AccessToken:[REDACTED] AuthURL:[REDACTED]}`,
+		"nothing secret":                     "nothing secret",
+	} {
+		if redactedString := redactString(str); redactedString != expected {
+			t.Errorf("expected %q, but got %q", expected, redactedString)
+		}
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	if defaultLogger() != defaultLogger() {
+		t.Errorf("default logger should not be rebuilt")
+	}
+
+	original, verbose := slog.Default(), Verbose
+	defer func() {
+		slog.SetDefault(original)
+		Verbose = verbose
+	}()
+	Verbose = false
+
+	var b bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&b, nil)))
+	defaultLogger().Info("changed", "access_token", "secret")
+	if output := b.String(); !strings.Contains(output, "changed") || strings.Contains(output, "secret") {
+		t.Errorf("unexpected output of the changed default logger: %s", output)
+	}
+}
@@ -0,0 +1,257 @@
+package telegraph
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Multi-account management
+//
+// Manager keeps clients of multiple accounts keyed by tenant ID,
+// loading them lazily from token stores, and sharing one HTTP client and rate limiter.
+
+// ManagerOptions is the options for creating a Manager.
+type ManagerOptions struct {
+	// TokenStore returns the TokenStore of given tenant (required).
+	//
+	// eg. func(tenantID string) TokenStore { return NewFileTokenStore(filepath.Join(dir, tenantID)) }
+	TokenStore func(tenantID string) TokenStore
+
+	// NewAccount returns the account information for creating a new account of given tenant,
+	// when there is no access token in its TokenStore (optional; if nil, ErrNoAccessToken is returned instead).
+	NewAccount func(tenantID string) Account
+
+	HTTPClient  *http.Client  // HTTP client shared by all clients (default: a new one with the default transport settings)
+	RateLimiter *RateLimiter  // rate limiter shared by all clients (optional)
+	IdleTimeout time.Duration // clients not used for this duration are evicted (0 = never)
+
+	ClientOptions []ClientOption // additional options applied to all clients
+}
+
+// TenantStats is the statistics of a tenant's client in Manager.
+type TenantStats struct {
+	ClientStats
+
+	LoadedAt   time.Time `json:"loaded_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// Manager manages clients of multiple tenants.
+type Manager struct {
+	options ManagerOptions
+
+	mu      sync.Mutex
+	tenants map[string]*managedClient
+
+	stop chan struct{} // guarded by mu
+	done chan struct{} // guarded by mu
+}
+
+// a client managed by Manager
+type managedClient struct {
+	ready chan struct{} // closed when loaded
+
+	client   *Client
+	err      error
+	loadedAt time.Time
+	lastUsed time.Time
+}
+
+// NewManager creates a new Manager with given options.
+//
+// If `options.IdleTimeout` is positive, idle clients are evicted periodically until Close is called.
+func NewManager(options ManagerOptions) *Manager {
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Transport: defaultHTTPClient.Transport.(*http.Transport).Clone()}
+	}
+
+	m := &Manager{
+		options: options,
+		tenants: map[string]*managedClient{},
+	}
+
+	if options.IdleTimeout > 0 {
+		m.stop = make(chan struct{})
+		m.done = make(chan struct{})
+
+		go m.evictPeriodically(options.IdleTimeout, m.stop, m.done)
+	}
+
+	return m
+}
+
+// Client returns the client of given tenant, loading (or creating) it if needed.
+func (m *Manager) Client(tenantID string) (*Client, error) {
+	m.mu.Lock()
+	managed, exists := m.tenants[tenantID]
+	if !exists {
+		managed = &managedClient{ready: make(chan struct{})}
+		m.tenants[tenantID] = managed
+	}
+	m.mu.Unlock()
+
+	if !exists {
+		managed.client, managed.err = m.load(tenantID)
+		managed.loadedAt = time.Now()
+		close(managed.ready)
+
+		if managed.err != nil { // do not keep failed ones
+			m.mu.Lock()
+			if m.tenants[tenantID] == managed {
+				delete(m.tenants, tenantID)
+			}
+			m.mu.Unlock()
+		}
+	} else {
+		<-managed.ready
+	}
+
+	if managed.err != nil {
+		return nil, managed.err
+	}
+
+	m.mu.Lock()
+	managed.lastUsed = time.Now()
+	m.mu.Unlock()
+
+	return managed.client, nil
+}
+
+// load the client of given tenant from its token store, or create a new account
+func (m *Manager) load(tenantID string) (client *Client, err error) {
+	if m.options.TokenStore == nil {
+		return nil, errors.New("no token store function in manager options")
+	}
+
+	store := m.options.TokenStore(tenantID)
+	options := append([]ClientOption{
+		WithTokenStore(store),
+		WithHTTPClient(m.options.HTTPClient),
+		WithRateLimiter(m.options.RateLimiter),
+	}, m.options.ClientOptions...)
+
+	var token string
+	if token, err = store.Load(); err == nil {
+		return NewClient(token, options...), nil
+	}
+
+	if errors.Is(err, ErrNoAccessToken) && m.options.NewAccount != nil {
+		account := m.options.NewAccount(tenantID)
+		if client, err = Create(account.ShortName, account.AuthorName, account.AuthorURL, options...); err == nil {
+			return client, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to load client of tenant '%s': %w", tenantID, err)
+}
+
+// Evict removes the client of given tenant from the manager.
+//
+// It will be loaded again on the next call of Client.
+func (m *Manager) Evict(tenantID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tenants, tenantID)
+}
+
+// EvictIdle removes clients which have not been used for given duration,
+// and returns the number of evicted ones.
+func (m *Manager) EvictIdle(idle time.Duration) (evicted int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	threshold := time.Now().Add(-idle)
+	for tenantID, managed := range m.tenants {
+		if !isClosed(managed.ready) {
+			continue // still loading
+		}
+		if managed.lastUsed.Before(threshold) {
+			delete(m.tenants, tenantID)
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+// Tenants returns IDs of tenants whose clients are loaded.
+func (m *Manager) Tenants() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenants := make([]string, 0, len(m.tenants))
+	for tenantID, managed := range m.tenants {
+		if isClosed(managed.ready) && managed.err == nil {
+			tenants = append(tenants, tenantID)
+		}
+	}
+
+	return tenants
+}
+
+// Stats returns the statistics of loaded clients, keyed by tenant ID.
+func (m *Manager) Stats() map[string]TenantStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := map[string]TenantStats{}
+	for tenantID, managed := range m.tenants {
+		if isClosed(managed.ready) && managed.err == nil {
+			stats[tenantID] = TenantStats{
+				ClientStats: managed.client.Stats(),
+				LoadedAt:    managed.loadedAt,
+				LastUsedAt:  managed.lastUsed,
+			}
+		}
+	}
+
+	return stats
+}
+
+// Close stops evicting idle clients periodically.
+//
+// It can be called multiple times, and calls after the first one do nothing.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done // without the lock, as evicting takes it
+	}
+}
+
+// evict idle clients periodically until `stop` is closed
+func (m *Manager) evictPeriodically(idle time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(max(idle/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if evicted := m.EvictIdle(idle); evicted > 0 {
+				defaultLogger().Debug("evicted idle clients", "count", evicted)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// check if given channel is closed
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,141 @@
+package telegraph
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RoundTripper which returns canned responses
+type cannedTransport struct {
+	mu        sync.Mutex
+	responses []string
+	requests  int
+	bodies    []string // bodies of sent requests
+}
+
+func (t *cannedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if req.Body != nil {
+		sent, _ := io.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(sent))
+	}
+
+	body := t.responses[min(t.requests, len(t.responses)-1)]
+	t.requests++
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestManager(t *testing.T) {
+	stores := map[string]TokenStore{
+		"tenant-a": NewMemoryTokenStore("token-a"),
+		"tenant-b": NewMemoryTokenStore(""),
+	}
+	transport := &cannedTransport{responses: []string{`{"ok":true,"result":{"short_name":"b","access_token":"token-b"}}`}}
+
+	manager := NewManager(ManagerOptions{
+		TokenStore: func(tenantID string) TokenStore { return stores[tenantID] },
+		NewAccount: func(tenantID string) Account { return Account{ShortName: tenantID} },
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	defer manager.Close()
+
+	a1, err := manager.Client("tenant-a")
+	if err != nil || a1.AccessToken() != "token-a" {
+		t.Fatalf("unexpected client: %#+v, %v", a1, err)
+	}
+	if a2, _ := manager.Client("tenant-a"); a1 != a2 {
+		t.Errorf("client should be reused")
+	}
+
+	// account is created for a tenant without access token
+	b, err := manager.Client("tenant-b")
+	if err != nil || b.AccessToken() != "token-b" {
+		t.Fatalf("unexpected client: %#+v, %v", b, err)
+	}
+	if token, _ := stores["tenant-b"].Load(); token != "token-b" {
+		t.Errorf("created access token was not saved: %s", token)
+	}
+
+	stats := manager.Stats()
+	if len(stats) != 2 || stats["tenant-a"].LastUsedAt.IsZero() {
+		t.Errorf("unexpected stats: %#+v", stats)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if evicted := manager.EvictIdle(5 * time.Millisecond); evicted != 2 {
+		t.Errorf("expected 2 evicted clients, but got %d", evicted)
+	}
+	if a3, _ := manager.Client("tenant-a"); a3 == a1 {
+		t.Errorf("evicted client should be reloaded")
+	}
+}
+
+func TestManagerClose(t *testing.T) {
+	manager := NewManager(ManagerOptions{IdleTimeout: time.Minute})
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Go(manager.Close)
+	}
+	wg.Wait()
+
+	manager.Close() // no-op
+}
+
+func TestFloodWaitRetries(t *testing.T) {
+	transport := &cannedTransport{responses: []string{
+		`{"ok":false,"error":"FLOOD_WAIT_0"}`,
+		`{"ok":true,"result":{"views":42}}`,
+	}}
+	client := NewClient("", WithHTTPClient(&http.Client{Transport: transport}), WithFloodWaitRetries(1))
+
+	views, err := client.GetViews("path", 0, 0, 0, -1)
+	if err != nil || views.Views != 42 {
+		t.Errorf("unexpected result: %#+v, %v", views, err)
+	}
+	if stats := client.Stats(); stats.Requests != 2 || stats.FloodWaits != 1 {
+		t.Errorf("unexpected stats: %#+v", stats)
+	}
+
+	// without retries
+	transport = &cannedTransport{responses: []string{`{"ok":false,"error":"FLOOD_WAIT_3"}`}}
+	client = NewClient("", WithHTTPClient(&http.Client{Transport: transport}))
+
+	var floodWait *FloodWaitError
+	if _, err := client.GetViews("path", 0, 0, 0, -1); !errors.As(err, &floodWait) || floodWait.RetryAfter != 3*time.Second {
+		t.Errorf("expected FloodWaitError, but got: %v", err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(10, 2)
+	now := time.Now()
+
+	if delay := limiter.reserve(now); delay != 0 {
+		t.Errorf("first request should not be delayed: %s", delay)
+	}
+	if delay := limiter.reserve(now); delay != 0 {
+		t.Errorf("second request should not be delayed: %s", delay)
+	}
+	if delay := limiter.reserve(now); delay != 100*time.Millisecond {
+		t.Errorf("third request should be delayed for 100ms: %s", delay)
+	}
+
+	limiter.Pause(time.Hour)
+	if delay := limiter.reserve(time.Now()); delay < 59*time.Minute {
+		t.Errorf("request should be delayed while paused: %s", delay)
+	}
+}
@@ -0,0 +1,326 @@
+package telegraph
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Markdown helpers
+//
+// Only a subset of Markdown which can be represented with Telegraph's nodes is supported:
+// headings, paragraphs, block quotes, lists, fenced code blocks, horizontal rules, pipe tables,
+// emphasis, strikethrough, inline codes, links, and images.
+
+var (
+	mdHeadingRegex  = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?\s*$`) // closing #s should follow a whitespace
+	mdHRRegex       = regexp.MustCompile(`^([-*_])(\s*([-*_])){2,}\s*$`)
+	mdListItemRegex = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+(.*)$`)
+	mdTableDelimRow = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// characters which can be escaped with a backslash
+const mdEscapable = "\\`*_{}[]()#+-.!~<>|"
+
+// NewNodesWithMarkdown creates new nodes with given Markdown string.
+//
+// Like NewNodesWithHTML, bare links to embeddable contents on their own lines are converted to embeds,
+// and tables are converted with given options (see WithTableOptions).
+func NewNodesWithMarkdown(markdown string, options ...ConvertOption) ([]Node, error) {
+	return NewNodesWithHTML(markdownToHTML(markdown), options...)
+}
+
+// convert Markdown string to HTML
+func markdownToHTML(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + markdownInlineToHTML(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"): // fenced code block
+			flush()
+
+			fence := trimmed[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre>" + html.EscapeString(strings.Join(code, "\n")) + "</pre>\n")
+		case mdHeadingRegex.MatchString(trimmed):
+			flush()
+
+			matches := mdHeadingRegex.FindStringSubmatch(trimmed)
+			tag := "h3" // telegraph supports only h3 and h4
+			if len(matches[1]) > 2 {
+				tag = "h4"
+			}
+			b.WriteString("<" + tag + ">" + markdownInlineToHTML(matches[2]) + "</" + tag + ">\n")
+		case mdHRRegex.MatchString(trimmed):
+			flush()
+
+			b.WriteString("<hr>\n")
+		case strings.HasPrefix(trimmed, ">"): // block quote
+			flush()
+
+			var quoted []string
+			for ; i < len(lines); i++ {
+				line := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(line, ">") {
+					i--
+					break
+				}
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimPrefix(line, ">"), " "))
+			}
+			b.WriteString("<blockquote>" + strings.TrimSpace(markdownToHTML(strings.Join(quoted, "\n"))) + "</blockquote>\n")
+		case mdListItemRegex.MatchString(lines[i]):
+			flush()
+
+			tag := "ul"
+			if marker := mdListItemRegex.FindStringSubmatch(lines[i])[1]; marker[0] >= '0' && marker[0] <= '9' {
+				tag = "ol"
+			}
+
+			var items []string
+			for ; i < len(lines); i++ {
+				line := lines[i]
+				if matches := mdListItemRegex.FindStringSubmatch(line); matches != nil {
+					items = append(items, matches[2])
+				} else if len(items) > 0 && strings.TrimSpace(line) != "" && (line[0] == ' ' || line[0] == '\t') {
+					items[len(items)-1] += "\n" + strings.TrimSpace(line) // continuation of the last item
+				} else {
+					i--
+					break
+				}
+			}
+
+			b.WriteString("<" + tag + ">")
+			for _, item := range items {
+				b.WriteString("<li>" + markdownInlineToHTML(item) + "</li>")
+			}
+			b.WriteString("</" + tag + ">\n")
+		case strings.Contains(trimmed, "|") && i+1 < len(lines) && strings.Contains(lines[i+1], "|") && mdTableDelimRow.MatchString(strings.TrimSpace(lines[i+1])): // pipe table
+			flush()
+
+			header := splitMarkdownTableRow(trimmed)
+			aligns := []string{}
+			for _, delim := range splitMarkdownTableRow(strings.TrimSpace(lines[i+1])) {
+				switch {
+				case strings.HasPrefix(delim, ":") && strings.HasSuffix(delim, ":"):
+					aligns = append(aligns, "center")
+				case strings.HasSuffix(delim, ":"):
+					aligns = append(aligns, "right")
+				default:
+					aligns = append(aligns, "")
+				}
+			}
+			writeRow := func(cells []string, tag string) {
+				b.WriteString("<tr>")
+				for j := range header { // same number of cells as the header
+					attrs := ""
+					if j < len(aligns) && aligns[j] != "" {
+						attrs = ` align="` + aligns[j] + `"`
+					}
+					cell := ""
+					if j < len(cells) {
+						cell = cells[j]
+					}
+					b.WriteString("<" + tag + attrs + ">" + markdownInlineToHTML(cell) + "</" + tag + ">")
+				}
+				b.WriteString("</tr>")
+			}
+
+			b.WriteString("<table><thead>")
+			writeRow(header, "th")
+			b.WriteString("</thead><tbody>")
+			for i += 2; i < len(lines); i++ {
+				line := strings.TrimSpace(lines[i])
+				if line == "" || !strings.Contains(line, "|") {
+					i--
+					break
+				}
+				writeRow(splitMarkdownTableRow(line), "td")
+			}
+			b.WriteString("</tbody></table>\n")
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	return b.String()
+}
+
+// split a row of a pipe table into cells (escaped pipes are kept in cells)
+func splitMarkdownTableRow(row string) (cells []string) {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	if strings.HasSuffix(row, "\\") { // trailing escaped pipe was trimmed
+		row += "|"
+	}
+
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// convert inline Markdown string to HTML
+func markdownInlineToHTML(str string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(str); {
+		c := str[i]
+
+		switch {
+		case c == '\\' && i+1 < len(str) && strings.IndexByte(mdEscapable, str[i+1]) >= 0: // escaped character
+			b.WriteString(html.EscapeString(str[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`': // inline code
+			if end := strings.IndexByte(str[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(str[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case c == '!' && i+1 < len(str) && str[i+1] == '[': // image
+			if text, dest, n, ok := parseMarkdownLink(str[i+1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(text) + `">`)
+				i += n + 1
+				continue
+			}
+		case c == '[': // link
+			if text, dest, n, ok := parseMarkdownLink(str[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `">` + markdownInlineToHTML(text) + `</a>`)
+				i += n
+				continue
+			}
+		case c == '<': // autolink
+			if end := strings.IndexByte(str[i:], '>'); end > 0 {
+				if link := str[i+1 : i+end]; (strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://")) && !strings.ContainsAny(link, " <") {
+					b.WriteString(`<a href="` + html.EscapeString(link) + `">` + html.EscapeString(link) + `</a>`)
+					i += end + 1
+					continue
+				}
+			}
+		case strings.HasPrefix(str[i:], "**") || strings.HasPrefix(str[i:], "__"):
+			if inner, n, ok := findMarkdownDelimited(str[i:], str[i:i+2]); ok {
+				b.WriteString("<b>" + markdownInlineToHTML(inner) + "</b>")
+				i += n
+				continue
+			}
+		case strings.HasPrefix(str[i:], "~~"):
+			if inner, n, ok := findMarkdownDelimited(str[i:], "~~"); ok {
+				b.WriteString("<s>" + markdownInlineToHTML(inner) + "</s>")
+				i += n
+				continue
+			}
+		case c == '*' || c == '_':
+			if c == '_' && i > 0 && isMarkdownWordByte(str[i-1]) {
+				break // intraword underscores are not emphasis
+			}
+			if inner, n, ok := findMarkdownDelimited(str[i:], str[i:i+1]); ok {
+				b.WriteString("<i>" + markdownInlineToHTML(inner) + "</i>")
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(str[i : i+1]))
+		i++
+	}
+
+	return b.String()
+}
+
+// parse `[text](destination)` at the beginning of given string,
+// and return its text, destination, and the number of consumed bytes
+func parseMarkdownLink(str string) (text, dest string, n int, ok bool) {
+	depth := 0
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 >= len(str) || str[i+1] != '(' {
+					return "", "", 0, false
+				}
+				end := strings.IndexByte(str[i+2:], ')')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				dest = strings.TrimSpace(str[i+2 : i+2+end])
+				if space := strings.IndexAny(dest, " \t"); space >= 0 { // strip optional title
+					dest = dest[:space]
+				}
+				return str[1:i], strings.Trim(dest, "<>"), i + 3 + end, true
+			}
+		}
+	}
+
+	return "", "", 0, false
+}
+
+// find a string enclosed with given delimiter at the beginning of given string,
+// and return the enclosed string and the number of consumed bytes
+func findMarkdownDelimited(str, delimiter string) (inner string, n int, ok bool) {
+	rest := str[len(delimiter):]
+	if len(rest) == 0 || rest[0] == ' ' {
+		return "", 0, false
+	}
+
+	for offset := 0; offset < len(rest); {
+		end := strings.Index(rest[offset:], delimiter)
+		if end < 0 {
+			break
+		}
+		end += offset
+
+		// closing delimiter should not be a part of a longer run
+		run := len(delimiter)
+		if len(delimiter) == 1 {
+			for end+run < len(rest) && rest[end+run] == delimiter[0] {
+				run++
+			}
+		}
+
+		// and should not be preceded by a space
+		if run == len(delimiter) && end > 0 && rest[end-1] != ' ' {
+			return rest[:end], len(delimiter)*2 + end, true
+		}
+		offset = end + run
+	}
+
+	return "", 0, false
+}
+
+// check if given byte can be a part of a word
+func isMarkdownWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,42 @@
+package telegraph
+
+import (
+	"testing"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	for markdown, expected := range map[string]string{
+		"# Title":                          "<h3>Title</h3>\n",
+		"### Sub title":                    "<h4>Sub title</h4>\n",
+		"# C#":                             "<h3>C#</h3>\n",
+		"## Closed ##":                     "<h3>Closed</h3>\n",
+		"**bold** and *italic* `code`":     "<p><b>bold</b> and <i>italic</i> <code>code</code></p>\n",
+		"~~gone~~ snake_case_name":         "<p><s>gone</s> snake_case_name</p>\n",
+		"[link](http://example.com \"t\")": `<p><a href="http://example.com">link</a></p>` + "\n",
+		"![alt](http://example.com/a.png)": `<p><img src="http://example.com/a.png" alt="alt"></p>` + "\n",
+		"- one\n- two":                     "<ul><li>one</li><li>two</li></ul>\n",
+		"1. one\n2. two":                   "<ol><li>one</li><li>two</li></ol>\n",
+		"> quoted":                         "<blockquote><p>quoted</p></blockquote>\n",
+		"```go\na < b\n```":                "<pre>a &lt; b</pre>\n",
+		"* * *":                            "<hr>\n",
+		`\*not italic\*`:                   "<p>*not italic*</p>\n",
+		"<https://telegra.ph>":             `<p><a href="https://telegra.ph">https://telegra.ph</a></p>` + "\n",
+	} {
+		if converted := markdownToHTML(markdown); converted != expected {
+			t.Errorf("expected %q for %q, but got %q", expected, markdown, converted)
+		}
+	}
+}
+
+func TestNewNodesWithMarkdown(t *testing.T) {
+	nodes, err := NewNodesWithMarkdown("# Title\n\nThis is **bold**.")
+	if err != nil {
+		t.Fatalf("failed to convert markdown: %s", err)
+	}
+	if len(nodes) != 4 { // h3, "\n", p, "\n"
+		t.Fatalf("unexpected number of nodes: %#+v", nodes)
+	}
+	if element, ok := nodes[0].(NodeElement); !ok || element.Tag != "h3" {
+		t.Errorf("unexpected first node: %#+v", nodes[0])
+	}
+}
@@ -3,9 +3,10 @@ package telegraph
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
@@ -28,8 +29,6 @@ import (
 //
 // http://telegra.ph/api#createAccount
 func (c *Client) CreateAccount(shortName, authorName, authorURL string) (acc Account, err error) {
-	url := fmt.Sprintf("%s/%s", apiBaseURL, "createAccount")
-
 	// params
 	params := map[string]any{
 		"short_name": shortName,
@@ -41,7 +40,7 @@ func (c *Client) CreateAccount(shortName, authorName, authorURL string) (acc Acc
 		params["author_url"] = authorURL
 	}
 
-	return request[Account](url, params)
+	return request[Account](c, "createAccount", "", params)
 }
 
 // EditAccountInfo updates information about a Telegraph account.
@@ -52,11 +51,9 @@ func (c *Client) CreateAccount(shortName, authorName, authorURL string) (acc Acc
 //
 // http://telegra.ph/api#editAccountInfo
 func (c *Client) EditAccountInfo(shortName, authorName, authorURL string) (acc Account, err error) {
-	url := fmt.Sprintf("%s/%s", apiBaseURL, "editAccountInfo")
-
 	// params
 	params := map[string]any{
-		"access_token": c.AccessToken,
+		"access_token": c.AccessToken(),
 		"short_name":   shortName,
 	}
 	if len(authorName) > 0 { // optional
@@ -66,7 +63,7 @@ func (c *Client) EditAccountInfo(shortName, authorName, authorURL string) (acc A
 		params["author_url"] = authorURL
 	}
 
-	return request[Account](url, params)
+	return request[Account](c, "editAccountInfo", "", params)
 }
 
 // GetAccountInfo fetches information about a Telegraph account.
@@ -76,11 +73,9 @@ func (c *Client) EditAccountInfo(shortName, authorName, authorURL string) (acc A
 //
 // http://telegra.ph/api#getAccountInfo
 func (c *Client) GetAccountInfo(fields []string) (acc Account, err error) {
-	url := fmt.Sprintf("%s/%s", apiBaseURL, "getAccountInfo")
-
 	// params
 	params := map[string]any{
-		"access_token": c.AccessToken,
+		"access_token": c.AccessToken(),
 	}
 	if len(fields) > 0 { // optional
 		params["fields"] = fields
@@ -88,21 +83,26 @@ func (c *Client) GetAccountInfo(fields []string) (acc Account, err error) {
 		params["fields"] = []string{"short_name", "author_name", "author_url"} // default
 	}
 
-	return request[Account](url, params)
+	return request[Account](c, "getAccountInfo", "", params)
 }
 
 // RevokeAccessToken revokes access token and generate a new one.
 //
+// The client (and its TokenStore, if any) is atomically switched to the new access token,
+// and handlers registered with OnTokenRotated are notified.
+//
 // http://telegra.ph/api#revokeAccessToken
 func (c *Client) RevokeAccessToken() (acc Account, err error) {
-	url := fmt.Sprintf("%s/%s", apiBaseURL, "revokeAccessToken")
-
 	// params
 	params := map[string]any{
-		"access_token": c.AccessToken,
+		"access_token": c.AccessToken(),
+	}
+
+	if acc, err = request[Account](c, "revokeAccessToken", "", params); err == nil {
+		err = c.rotateAccessToken(acc.AccessToken)
 	}
 
-	return request[Account](url, params)
+	return acc, err
 }
 
 // CreatePage creates a new Telegraph page.
@@ -115,13 +115,11 @@ func (c *Client) RevokeAccessToken() (acc Account, err error) {
 //
 // http://telegra.ph/api#createPage
 func (c *Client) CreatePage(title, authorName, authorURL string, content []Node, returnContent bool) (page Page, err error) {
-	url := fmt.Sprintf("%s/%s", apiBaseURL, "createPage")
-
 	// params
 	params := map[string]any{
-		"access_token": c.AccessToken,
+		"access_token": c.AccessToken(),
 		"title":        title,
-		"content":      castNodes(content),
+		"content":      castNodes(c.logger(), content),
 	}
 	if len(authorName) > 0 { // optional
 		params["author_name"] = authorName
@@ -133,7 +131,11 @@ func (c *Client) CreatePage(title, authorName, authorURL string, content []Node,
 		params["return_content"] = returnContent
 	}
 
-	return request[Page](url, params)
+	if page, err = request[Page](c, "createPage", "", params); err == nil {
+		c.invalidatePageList()
+	}
+
+	return page, err
 }
 
 // CreatePageWithHTML creates a new page with HTML.
@@ -156,15 +158,26 @@ func (c *Client) CreatePageWithHTML(title, authorName, authorURL, htmlContent st
 // authorURL:  0-512 characters (optional)
 // returnContent: return edited Page object or not
 //
+// If the client has a RevisionStore, the current page is saved as a revision before editing.
+//
 // http://telegra.ph/api#editPage
 func (c *Client) EditPage(path, title string, content []Node, authorName, authorURL string, returnContent bool) (page Page, err error) {
-	url := fmt.Sprintf("%s/%s/%s", apiBaseURL, "editPage", path)
+	return c.editPage(RevisionReasonEdit, path, title, content, authorName, authorURL, returnContent)
+}
+
+// edit a page, saving the current one as a revision with given reason (no revision is saved if it is empty)
+func (c *Client) editPage(reason RevisionReason, path, title string, content []Node, authorName, authorURL string, returnContent bool) (page Page, err error) {
+	if reason != "" {
+		if _, err = c.snapshotPage(path, reason); err != nil {
+			return page, err
+		}
+	}
 
 	// params
 	params := map[string]any{
-		"access_token": c.AccessToken,
+		"access_token": c.AccessToken(),
 		"title":        title,
-		"content":      castNodes(content),
+		"content":      castNodes(c.logger(), content),
 	}
 	if len(authorName) > 0 { // optional
 		params["author_name"] = authorName
@@ -176,7 +189,11 @@ func (c *Client) EditPage(path, title string, content []Node, authorName, author
 		params["return_content"] = returnContent
 	}
 
-	return request[Page](url, params)
+	if page, err = request[Page](c, "editPage", path, params); err == nil {
+		c.invalidatePage(path)
+	}
+
+	return page, err
 }
 
 // GetPage fetches a Telegraph page.
@@ -186,14 +203,14 @@ func (c *Client) EditPage(path, title string, content []Node, authorName, author
 //
 // http://telegra.ph/api#getPage
 func (c *Client) GetPage(path string, returnContent bool) (page Page, err error) {
-	url := fmt.Sprintf("%s/%s/%s", apiBaseURL, "getPage", path)
-
 	// params
 	params := map[string]any{
 		"return_content": returnContent,
 	}
 
-	return request[Page](url, params)
+	return cached(c, func() string { return pageCacheKey(path, returnContent) }, func() (Page, error) {
+		return request[Page](c, "getPage", path, params)
+	})
 }
 
 // GetPageList fetches a list of pages belonging to a Telegraph account.
@@ -203,11 +220,9 @@ func (c *Client) GetPage(path string, returnContent bool) (page Page, err error)
 //
 // http://telegra.ph/api#getPageList
 func (c *Client) GetPageList(offset, limit int) (list PageList, err error) {
-	url := fmt.Sprintf("%s/%s", apiBaseURL, "getPageList")
-
 	// params
 	params := map[string]any{
-		"access_token": c.AccessToken,
+		"access_token": c.AccessToken(),
 	}
 	if offset > 0 { // optional
 		params["offset"] = offset
@@ -216,7 +231,9 @@ func (c *Client) GetPageList(offset, limit int) (list PageList, err error) {
 		params["limit"] = limit
 	}
 
-	return request[PageList](url, params)
+	return cached(c, func() string { return c.pageListCacheKey(offset, limit) }, func() (PageList, error) {
+		return request[PageList](c, "getPageList", "", params)
+	})
 }
 
 // GetViews fetches the number of views for a Telegraph page.
@@ -229,8 +246,6 @@ func (c *Client) GetPageList(offset, limit int) (list PageList, err error) {
 //
 // http://telegra.ph/api#getViews
 func (c *Client) GetViews(path string, year, month, day, hour int) (views PageViews, err error) {
-	url := fmt.Sprintf("%s/%s/%s", apiBaseURL, "getViews", path)
-
 	// params
 	params := map[string]any{}
 	if year > 0 { // optional
@@ -246,7 +261,9 @@ func (c *Client) GetViews(path string, year, month, day, hour int) (views PageVi
 		params["hour"] = hour
 	}
 
-	return request[PageViews](url, params)
+	return cached(c, func() string { return viewsCacheKey(path, year, month, day, hour) }, func() (PageViews, error) {
+		return request[PageViews](c, "getViews", path, params)
+	})
 }
 
 // NewNodeWithString creates a new node with given string.
@@ -265,11 +282,14 @@ func NewNodeWithElement(tag string, attrs map[string]string, children []Node) No
 }
 
 // NewNodesWithHTML creates new nodes with given HTML string.
-func NewNodesWithHTML(html string) ([]Node, error) {
+//
+// Bare links to embeddable contents (eg. YouTube videos, tweets) on their own lines are converted to embeds,
+// and tables are converted with given options (see WithTableOptions).
+func NewNodesWithHTML(html string, options ...ConvertOption) ([]Node, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 
 	if err == nil {
-		return traverseNodes(doc.Find("body").Contents()), nil
+		return convertNodes(traverseNodes(doc.Find("body").Contents()), options), nil
 	}
 
 	return nil, err
@@ -316,10 +336,22 @@ func traverseNodes(selections *goquery.Selection) []Node {
 	return nodes
 }
 
-// send HTTP POST request (www-form urlencoded)
-func httpPost(apiURL string, params map[string]any) (jsonBytes []byte, err error) {
-	v("sending post request to url: %s, params: %#v", apiURL, params)
+// default HTTP client, shared by clients without their own one
+var defaultHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Dial: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 300 * time.Second,
+		}).Dial,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	},
+}
 
+// send HTTP POST request (www-form urlencoded)
+func httpPost(client *http.Client, apiURL string, params map[string]any) (jsonBytes []byte, status int, err error) {
 	var js []byte
 	paramValues := url.Values{}
 	for key, value := range params {
@@ -329,9 +361,7 @@ func httpPost(apiURL string, params map[string]any) (jsonBytes []byte, err error
 			if js, err = json.Marshal(value); err == nil {
 				paramValues[key] = []string{string(js)}
 			} else {
-				l("param marshalling error for: %s (%s)", key, err)
-
-				return []byte{}, err
+				return []byte{}, 0, fmt.Errorf("param marshalling error for: %s (%s)", key, err)
 			}
 		}
 	}
@@ -343,46 +373,33 @@ func httpPost(apiURL string, params map[string]any) (jsonBytes []byte, err error
 		req.Header.Add("Content-Length", strconv.Itoa(len(encoded)))
 
 		var res *http.Response
-		client := &http.Client{
-			Transport: &http.Transport{
-				Dial: (&net.Dialer{
-					Timeout:   10 * time.Second,
-					KeepAlive: 300 * time.Second,
-				}).Dial,
-				IdleConnTimeout:       90 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ResponseHeaderTimeout: 10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-			},
-		}
-
 		res, err = client.Do(req)
 
 		if res != nil {
 			defer res.Body.Close()
+
+			status = res.StatusCode
 		}
 
 		if err == nil {
 			if jsonBytes, err = io.ReadAll(res.Body); err == nil {
-				return jsonBytes, nil
+				return jsonBytes, status, nil
 			}
 
-			l("resonse read error: %s", err.Error())
-		} else {
-			l("request error: %s", err.Error())
+			err = fmt.Errorf("response read error: %s", err)
 		}
 	} else {
-		l("building request error: %s", err.Error())
+		err = fmt.Errorf("building request error: %s", err)
 	}
 
-	return []byte{}, err
+	return []byte{}, status, err
 }
 
 // cast nodes for marshalling
-func castNodes(nodes []Node) []any {
+func castNodes(logger *slog.Logger, nodes []Node) []any {
 	castNodes := []any{}
 
-	for _, node := range nodes {
+	for _, node := range normalizeNodes(nodes) {
 		switch node.(type) {
 		case NodeElement:
 			castNodes = append(castNodes, node)
@@ -390,7 +407,7 @@ func castNodes(nodes []Node) []any {
 			if cast, ok := node.(string); ok {
 				castNodes = append(castNodes, cast)
 			} else {
-				l("param casting error: %#+v", node)
+				logger.Warn("param casting error", "node", fmt.Sprintf("%#+v", node))
 			}
 		}
 	}
@@ -398,34 +415,102 @@ func castNodes(nodes []Node) []any {
 	return castNodes
 }
 
-// for logging
-func l(format string, v ...any) {
-	log.Printf(format, v...)
-}
+// send HTTP request for APIResponse[T] and fetch its result.
+//
+// method: name of the API method (eg. "getPage")
+// path: path of the page (only for methods which need it)
+//
+// When the API responds with FLOOD_WAIT_X error, it waits and retries
+// as many times as the client's WithFloodWaitRetries option.
+func request[T any](c *Client, method, path string, params map[string]any) (result T, err error) {
+	url := fmt.Sprintf("%s/%s", apiBaseURL, method)
+	if len(path) > 0 {
+		url = fmt.Sprintf("%s/%s", url, path)
+	}
 
-// for logging verbosely
-func v(format string, v ...any) {
-	if Verbose {
-		l(format, v...)
+	logger := c.logger().With("method", method)
+	if len(path) > 0 {
+		logger = logger.With("path", path)
+	}
+	limiter := c.rateLimiter()
+	hooks := c.requestHooks()
+
+	for retries := 0; ; retries++ {
+		limiter.Wait()
+
+		info := RequestInfo{
+			Method:  method,
+			Path:    path,
+			Params:  redactedParams(params),
+			Retries: retries,
+		}
+		for _, hook := range hooks {
+			hook.BeforeRequest(info)
+		}
+		logger.Debug("sending request", "retries", retries, paramsAttr(info.Params))
+
+		start := time.Now()
+		result, err = requestOnce[T](c.httpClient(), url, params, &info)
+		info.Duration = time.Since(start)
+		c.recordRequest(err)
+
+		for _, hook := range hooks {
+			hook.AfterRequest(info)
+		}
+		attrs := []any{
+			"duration", info.Duration,
+			"status", info.Status,
+			"size", info.ResponseSize,
+			"retries", retries,
+		}
+
+		var floodWait *FloodWaitError
+		if errors.As(err, &floodWait) {
+			limiter.Pause(floodWait.RetryAfter) // pause all clients sharing the limiter
+
+			if retries < c.floodWaitRetries() {
+				logger.Warn("flood wait, retrying", append(attrs, "retry_after", floodWait.RetryAfter)...)
+
+				if limiter == nil {
+					time.Sleep(floodWait.RetryAfter)
+				}
+				continue
+			}
+		}
+
+		if err != nil {
+			logger.Error("request failed", append(attrs, "error", err, "error_class", info.ErrorClass)...)
+		} else {
+			logger.Debug("request succeeded", attrs...)
+		}
+
+		return result, err
 	}
 }
 
-// send HTTP request for APIResponse[T] and fetch its result.
-func request[T any](url string, params map[string]any) (result T, err error) {
+// send a HTTP request for APIResponse[T] once, and fill the response information in `info`
+func requestOnce[T any](client *http.Client, url string, params map[string]any, info *RequestInfo) (result T, err error) {
+	defer func() {
+		info.Err = err
+		info.ErrorClass = classifyError(err)
+	}()
+
 	var bytes []byte
-	if bytes, err = httpPost(url, params); err == nil {
+	if bytes, info.Status, err = httpPost(client, url, params); err == nil {
+		info.ResponseSize = len(bytes)
+
 		var res APIResponse[T]
 		if err = json.Unmarshal(bytes, &res); err == nil {
 			if res.Ok {
 				return res.Result, nil
 			} else {
-				return result /* = empty */, fmt.Errorf("erroneous response: %s", res.Error)
+				return result /* = empty */, newAPIError(res.Error)
 			}
 		}
 
-		err = fmt.Errorf("json parse error: %s (%s)", err, string(bytes))
+		err = &decodeError{fmt.Errorf("json parse error: %s (%s)", err, string(bytes))}
 	} else {
-		err = fmt.Errorf("request to '%s' failed with error: %s", url, err)
+		err = &networkError{fmt.Errorf("request to '%s' failed with error: %s", url, err)}
 	}
 
 	return result /* = empty */, err
@@ -0,0 +1,161 @@
+package telegraph
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metrics in Prometheus text format
+
+// DefaultMetricsBuckets is the default buckets (in seconds) of request duration histograms.
+var DefaultMetricsBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics is a RequestHook which collects counters and histograms of requests,
+// and exposes them in Prometheus text format as a http.Handler.
+//
+//	metrics := telegraph.NewMetrics()
+//	client := telegraph.NewClient(token, telegraph.WithRequestHooks(metrics))
+//	http.Handle("/metrics", metrics)
+type Metrics struct {
+	mu sync.Mutex
+
+	buckets []float64
+
+	inFlight      map[string]int64             // method => number of requests in flight
+	requests      map[metricsRequestKey]uint64 // (method, error class) => number of requests
+	responseBytes map[string]uint64            // method => sum of response sizes
+	durations     map[string]*metricsHistogram // method => histogram of durations
+}
+
+// key of request counters
+type metricsRequestKey struct {
+	method     string
+	errorClass ErrorClass
+}
+
+// histogram of durations
+type metricsHistogram struct {
+	counts []uint64 // cumulative counts for each bucket
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics creates a new Metrics with given buckets (in seconds) of request duration histograms.
+//
+// If no bucket is given, DefaultMetricsBuckets is used.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &Metrics{
+		buckets:       buckets,
+		inFlight:      map[string]int64{},
+		requests:      map[metricsRequestKey]uint64{},
+		responseBytes: map[string]uint64{},
+		durations:     map[string]*metricsHistogram{},
+	}
+}
+
+// BeforeRequest increases the number of requests in flight.
+func (m *Metrics) BeforeRequest(info RequestInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[info.Method]++
+}
+
+// AfterRequest records the result of a request.
+func (m *Metrics) AfterRequest(info RequestInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[info.Method]--
+	m.requests[metricsRequestKey{method: info.Method, errorClass: info.ErrorClass}]++
+	m.responseBytes[info.Method] += uint64(info.ResponseSize)
+
+	histogram, exists := m.durations[info.Method]
+	if !exists {
+		histogram = &metricsHistogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[info.Method] = histogram
+	}
+	seconds := info.Duration.Seconds()
+	for i, bucket := range m.buckets {
+		if seconds <= bucket {
+			histogram.counts[i]++
+		}
+	}
+	histogram.count++
+	histogram.sum += seconds
+}
+
+// ServeHTTP writes the metrics in Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_ = m.WriteText(w)
+}
+
+// WriteText writes the metrics in Prometheus text format to given writer.
+func (m *Metrics) WriteText(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP telegraph_requests_total Total number of Telegraph API requests.\n")
+	b.WriteString("# TYPE telegraph_requests_total counter\n")
+	keys := make([]metricsRequestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b metricsRequestKey) int {
+		if c := strings.Compare(a.method, b.method); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.errorClass), string(b.errorClass))
+	})
+	for _, key := range keys {
+		fmt.Fprintf(&b, "telegraph_requests_total{method=%s,error_class=%s} %d\n", quoteLabel(key.method), quoteLabel(string(key.errorClass)), m.requests[key])
+	}
+
+	b.WriteString("# HELP telegraph_requests_in_flight Number of Telegraph API requests in flight.\n")
+	b.WriteString("# TYPE telegraph_requests_in_flight gauge\n")
+	for _, method := range sortedKeys(m.inFlight) {
+		fmt.Fprintf(&b, "telegraph_requests_in_flight{method=%s} %d\n", quoteLabel(method), m.inFlight[method])
+	}
+
+	b.WriteString("# HELP telegraph_response_bytes_total Total size of Telegraph API responses in bytes.\n")
+	b.WriteString("# TYPE telegraph_response_bytes_total counter\n")
+	for _, method := range sortedKeys(m.responseBytes) {
+		fmt.Fprintf(&b, "telegraph_response_bytes_total{method=%s} %d\n", quoteLabel(method), m.responseBytes[method])
+	}
+
+	b.WriteString("# HELP telegraph_request_duration_seconds Duration of Telegraph API requests in seconds.\n")
+	b.WriteString("# TYPE telegraph_request_duration_seconds histogram\n")
+	for _, method := range sortedKeys(m.durations) {
+		histogram := m.durations[method]
+		label := quoteLabel(method)
+		for i, bucket := range m.buckets {
+			fmt.Fprintf(&b, "telegraph_request_duration_seconds_bucket{method=%s,le=\"%s\"} %d\n", label, strconv.FormatFloat(bucket, 'g', -1, 64), histogram.counts[i])
+		}
+		fmt.Fprintf(&b, "telegraph_request_duration_seconds_bucket{method=%s,le=\"+Inf\"} %d\n", label, histogram.count)
+		fmt.Fprintf(&b, "telegraph_request_duration_seconds_sum{method=%s} %s\n", label, strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "telegraph_request_duration_seconds_count{method=%s} %d\n", label, histogram.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quote a label value in Prometheus text format
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
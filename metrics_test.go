@@ -0,0 +1,40 @@
+package telegraph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics(0.5, 1)
+
+	transport := &cannedTransport{responses: []string{
+		`{"ok":true,"result":{"views":1}}`,
+		`{"ok":false,"error":"PAGE_NOT_FOUND"}`,
+	}}
+	client := NewClient("token", WithHTTPClient(&http.Client{Transport: transport}), WithRequestHooks(metrics))
+
+	_, _ = client.GetViews("path", 0, 0, 0, -1)
+	if _, err := client.GetViews("path", 0, 0, 0, -1); classifyError(err) != ErrorClassAPI {
+		t.Errorf("unexpected error class of %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	exposed := recorder.Body.String()
+
+	for _, line := range []string{
+		`telegraph_requests_total{method="getViews",error_class="api"} 1`,
+		`telegraph_requests_total{method="getViews",error_class="none"} 1`,
+		`telegraph_requests_in_flight{method="getViews"} 0`,
+		`telegraph_request_duration_seconds_bucket{method="getViews",le="0.5"} 2`,
+		`telegraph_request_duration_seconds_bucket{method="getViews",le="+Inf"} 2`,
+		`telegraph_request_duration_seconds_count{method="getViews"} 2`,
+	} {
+		if !strings.Contains(exposed, line+"\n") {
+			t.Errorf("missing line '%s' in:\n%s", line, exposed)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package telegraph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Page migration between accounts
+//
+// Telegraph cannot transfer pages between accounts, so pages are copied to the target account
+// with CreatePage, and links between them are rewritten to their new paths.
+
+// MigrateOptions is the options for migrating pages.
+type MigrateOptions struct {
+	Paths []string // paths of pages to migrate (default: all pages of the source account)
+
+	AuthorName string // author name of migrated pages (default: that of each page)
+	AuthorURL  string // author url of migrated pages (default: that of each page)
+
+	LeaveNotice bool   // replace contents of the old pages with a notice linking to the new ones
+	NoticeText  string // text of the notice (default: "This page has moved to: ")
+}
+
+// MigrationStage is a stage of migrating a page.
+type MigrationStage string
+
+// MigrationStage constants
+const (
+	MigrationStageFetch   MigrationStage = "fetch"   // fetching the old page with GetPage
+	MigrationStageCreate  MigrationStage = "create"  // creating the new page with CreatePage
+	MigrationStageRewrite MigrationStage = "rewrite" // rewriting links of the new page with EditPage
+	MigrationStageNotice  MigrationStage = "notice"  // leaving a notice on the old page with EditPage
+)
+
+// MigratedPage is a migrated page in MigrationReport.
+type MigratedPage struct {
+	OldPath        string `json:"old_path"`
+	OldURL         string `json:"old_url"`
+	NewPath        string `json:"new_path"`
+	NewURL         string `json:"new_url"`
+	LinksRewritten bool   `json:"links_rewritten"` // links to other migrated pages were rewritten
+	NoticeLeft     bool   `json:"notice_left"`     // a notice was left on the old page
+}
+
+// MigrationFailure is a failure in MigrationReport.
+type MigrationFailure struct {
+	Path  string         `json:"path"` // path of the old page
+	Stage MigrationStage `json:"stage"`
+	Err   error          `json:"-"`
+}
+
+// MigrationReport is the result of MigratePages.
+type MigrationReport struct {
+	Migrated []MigratedPage
+	Failed   []MigrationFailure
+}
+
+// Mapping returns a mapping of old paths to new ones of migrated pages.
+func (r MigrationReport) Mapping() map[string]string {
+	mapping := map[string]string{}
+	for _, page := range r.Migrated {
+		mapping[page.OldPath] = page.NewPath
+	}
+
+	return mapping
+}
+
+// MigratePages copies pages from the source account to the target one (the oldest first),
+// rewriting links between migrated pages to their new paths.
+//
+// Failures do not stop the migration; they are returned in the report (and as a joined error).
+// Pages which failed in the rewrite or notice stage are still migrated.
+func MigratePages(from, to API, options MigrateOptions) (report MigrationReport, err error) {
+	paths := options.Paths
+	if len(paths) == 0 {
+		var pages []Page
+		if pages, err = ListAllPages(from); err != nil {
+			return report, fmt.Errorf("failed to list pages: %w", err)
+		}
+		for _, page := range slices.Backward(pages) {
+			paths = append(paths, page.Path)
+		}
+	}
+	if options.NoticeText == "" {
+		options.NoticeText = "This page has moved to: "
+	}
+
+	errs := []error{}
+	fail := func(path string, stage MigrationStage, err error) {
+		report.Failed = append(report.Failed, MigrationFailure{Path: path, Stage: stage, Err: err})
+		errs = append(errs, fmt.Errorf("failed to %s page '%s': %w", stage, path, err))
+	}
+
+	// copy pages
+	pages := map[string]Page{} // old path => old page with content
+	for _, path := range paths {
+		page, err := from.GetPage(path, true)
+		if err != nil {
+			fail(path, MigrationStageFetch, err)
+			continue
+		}
+		pages[path] = page
+
+		authorName, authorURL := options.author(page)
+		created, err := to.CreatePage(page.Title, authorName, authorURL, page.Content, false)
+		if err != nil {
+			fail(path, MigrationStageCreate, err)
+			continue
+		}
+		report.Migrated = append(report.Migrated, MigratedPage{
+			OldPath: page.Path,
+			OldURL:  page.URL,
+			NewPath: created.Path,
+			NewURL:  created.URL,
+		})
+	}
+
+	// rewrite links between migrated pages
+	mapping := report.Mapping()
+	for i, migrated := range report.Migrated {
+		page := pages[migrated.OldPath]
+
+		content, changed := RewriteLinks(page.Content, mapping)
+		if !changed {
+			continue
+		}
+
+		authorName, authorURL := options.author(page)
+		if _, err := to.EditPage(migrated.NewPath, page.Title, content, authorName, authorURL, false); err != nil {
+			fail(migrated.OldPath, MigrationStageRewrite, err)
+			continue
+		}
+		report.Migrated[i].LinksRewritten = true
+	}
+
+	// leave notices on the old pages
+	if options.LeaveNotice {
+		for i, migrated := range report.Migrated {
+			page := pages[migrated.OldPath]
+
+			notice := []Node{NodeElement{Tag: "p", Children: []Node{
+				options.NoticeText,
+				NodeElement{Tag: "a", Attrs: map[string]string{"href": migrated.NewURL}, Children: []Node{migrated.NewURL}},
+			}}}
+			if _, err := from.EditPage(migrated.OldPath, page.Title, notice, page.AuthorName, page.AuthorURL, false); err != nil {
+				fail(migrated.OldPath, MigrationStageNotice, err)
+				continue
+			}
+			report.Migrated[i].NoticeLeft = true
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// author of a migrated page
+func (o MigrateOptions) author(page Page) (name, url string) {
+	name, url = page.AuthorName, page.AuthorURL
+	if o.AuthorName != "" {
+		name = o.AuthorName
+	}
+	if o.AuthorURL != "" {
+		url = o.AuthorURL
+	}
+
+	return name, url
+}
+
+// prefixes of links to Telegraph pages
+var pageLinkPrefixes = []string{"https://telegra.ph/", "http://telegra.ph/", "//telegra.ph/", "/"}
+
+// RewriteLinks returns nodes whose links to Telegraph pages are rewritten with given mapping of old paths to new ones,
+// and whether any link was rewritten.
+//
+// Both relative (eg. "/Old-Page-01-01") and absolute (eg. "https://telegra.ph/Old-Page-01-01") links are rewritten,
+// keeping their forms, queries, and fragments.
+func RewriteLinks(nodes []Node, mapping map[string]string) (rewritten []Node, changed bool) {
+	rewritten = []Node{}
+
+	for _, node := range nodes {
+		switch n := normalizeNode(node).(type) {
+		case string:
+			rewritten = append(rewritten, n)
+		case NodeElement:
+			var childrenChanged bool
+			n.Children, childrenChanged = RewriteLinks(n.Children, mapping)
+			changed = changed || childrenChanged
+
+			if href, exists := n.Attrs["href"]; exists && n.Tag == "a" {
+				if newHref, ok := rewriteLink(href, mapping); ok {
+					attrs := map[string]string{}
+					for key, value := range n.Attrs {
+						attrs[key] = value
+					}
+					attrs["href"] = newHref
+					n.Attrs = attrs
+					changed = true
+				}
+			}
+
+			rewritten = append(rewritten, n)
+		}
+	}
+
+	return rewritten, changed
+}
+
+// rewrite a link with given mapping
+func rewriteLink(href string, mapping map[string]string) (string, bool) {
+	for _, prefix := range pageLinkPrefixes {
+		if !strings.HasPrefix(href, prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(href, prefix)
+		path, suffix := rest, ""
+		if i := strings.IndexAny(rest, "?#"); i >= 0 {
+			path, suffix = rest[:i], rest[i:]
+		}
+		if newPath, exists := mapping[path]; exists && path != "" {
+			return prefix + newPath + suffix, true
+		}
+
+		return href, false
+	}
+
+	return href, false
+}
@@ -0,0 +1,35 @@
+package telegraph_test
+
+import (
+	"strings"
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+	"github.com/meinside/telegraph-go/telegraphtest"
+)
+
+func TestMigratePages(t *testing.T) {
+	from, to := telegraphtest.NewFake(), telegraphtest.NewFake()
+	from.AddPage(telegraph.Page{Path: "Index-01-01", Title: "Index", Content: []telegraph.Node{
+		telegraph.NewNodeWithElement("a", map[string]string{"href": "https://telegra.ph/Post-01-02#top"}, []telegraph.Node{"post"}),
+		telegraph.NewNodeWithElement("a", map[string]string{"href": "/Other-01-01"}, []telegraph.Node{"other"}),
+	}})
+	from.AddPage(telegraph.Page{Path: "Post-01-02", Title: "Post", Content: []telegraph.Node{"post"}})
+
+	report, err := telegraph.MigratePages(from, to, telegraph.MigrateOptions{LeaveNotice: true, AuthorName: "Team"})
+	if err != nil || len(report.Migrated) != 2 || len(report.Failed) != 0 {
+		t.Fatalf("unexpected report: %#+v, %v", report, err)
+	}
+
+	mapping := report.Mapping()
+	index, _ := to.Page(mapping["Index-01-01"])
+	expected := `<a href="https://telegra.ph/` + mapping["Post-01-02"] + `#top">post</a><a href="/Other-01-01">other</a>`
+	if html := telegraph.RenderHTML(index.Content); html != expected || index.AuthorName != "Team" || !report.Migrated[0].LinksRewritten {
+		t.Errorf("unexpected migrated page: %s (%#+v)", html, index)
+	}
+
+	old, _ := from.Page("Post-01-02")
+	if html := telegraph.RenderHTML(old.Content); !strings.Contains(html, "moved to") || !strings.Contains(html, mapping["Post-01-02"]) {
+		t.Errorf("unexpected notice: %s", html)
+	}
+}
@@ -0,0 +1,101 @@
+package telegraph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Rate limiting
+
+// RateLimiter limits the rate of requests with a token bucket.
+//
+// It can be shared by multiple clients (with WithRateLimiter) so that they share one request budget.
+// All methods are no-op on a nil *RateLimiter.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	rate  float64 // requests per second
+	burst float64
+
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter which allows `requestsPerSecond` requests per second,
+// with bursts of at most `burst` requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// Wait blocks until a request is allowed.
+func (l *RateLimiter) Wait() {
+	_ = l.WaitContext(context.Background())
+}
+
+// WaitContext blocks until a request is allowed, or given context is done.
+func (l *RateLimiter) WaitContext(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pause blocks all requests for given duration (eg. on FLOOD_WAIT_X errors).
+func (l *RateLimiter) Pause(duration time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(duration); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// reserve a token, and return the duration to wait for it
+func (l *RateLimiter) reserve(now time.Time) (delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// refill tokens
+	if !l.last.IsZero() && l.rate > 0 {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens < 0 && l.rate > 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if paused := l.pausedUntil.Sub(now); paused > delay {
+		delay = paused
+	}
+
+	return delay
+}
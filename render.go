@@ -0,0 +1,91 @@
+package telegraph
+
+import (
+	"html"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Rendering nodes
+
+// void elements which cannot have children
+var voidTags = []string{"br", "hr", "img"}
+
+// RenderHTML renders given nodes as a HTML string.
+func RenderHTML(nodes []Node) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		renderNodeHTML(&b, node, false)
+	}
+
+	return b.String()
+}
+
+// RenderXHTML renders given nodes as a well-formed XHTML fragment.
+func RenderXHTML(nodes []Node) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		renderNodeHTML(&b, node, true)
+	}
+
+	return b.String()
+}
+
+// render a node as HTML
+func renderNodeHTML(b *strings.Builder, node Node, xhtml bool) {
+	switch n := normalizeNode(node).(type) {
+	case string:
+		b.WriteString(html.EscapeString(n))
+	case NodeElement:
+		if n.Tag == "" {
+			for _, child := range n.Children {
+				renderNodeHTML(b, child, xhtml)
+			}
+			return
+		}
+
+		b.WriteString("<" + n.Tag)
+		keys := make([]string, 0, len(n.Attrs))
+		for key := range n.Attrs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b.WriteString(" " + key + `="` + html.EscapeString(n.Attrs[key]) + `"`)
+		}
+
+		if slices.Contains(voidTags, n.Tag) {
+			if xhtml {
+				b.WriteString("/>")
+			} else {
+				b.WriteString(">")
+			}
+			return
+		}
+
+		b.WriteString(">")
+		for _, child := range n.Children {
+			renderNodeHTML(b, child, xhtml)
+		}
+		b.WriteString("</" + n.Tag + ">")
+	}
+}
+
+// NodesText returns the text content of given nodes.
+func NodesText(nodes []Node) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch n := normalizeNode(node).(type) {
+		case string:
+			b.WriteString(n)
+		case NodeElement:
+			if n.Tag == "br" {
+				b.WriteString("\n")
+			}
+			b.WriteString(NodesText(n.Children))
+		}
+	}
+
+	return b.String()
+}
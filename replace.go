@@ -0,0 +1,150 @@
+package telegraph
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Account-wide find and replace
+//
+// ReplaceInPages transforms texts and urls of all pages in an account,
+// and edits only the pages which were actually changed:
+//
+//	changes, err := telegraph.ReplaceInPages(client, telegraph.ReplaceOptions{
+//		Text:   telegraph.ReplaceString("OldName", "NewName"),
+//		URL:    telegraph.ReplaceString("https://old.example.com", "https://new.example.com"),
+//		DryRun: true, // only for printing diffs of changes
+//	})
+
+// ReplaceOptions is the options for ReplaceInPages.
+type ReplaceOptions struct {
+	Text func(text string) string // transforms texts of nodes, and titles (optional)
+	URL  func(url string) string  // transforms href and src attributes (optional)
+
+	Paths  []string // paths of pages to transform (default: all pages of the account)
+	DryRun bool     // do not edit pages, only return changes
+}
+
+// PageChange is a change of a page by ReplaceInPages.
+type PageChange struct {
+	Path   string
+	Title  string // new title
+	Diff   string // diff of the old and new pages (see DiffPages)
+	Edited bool   // edited with EditPage (false for dry runs and failures)
+	Err    error  // error of editing the page
+}
+
+// ReplaceString returns a function which replaces all `old` strings with `new` one.
+func ReplaceString(old, new string) func(string) string {
+	return func(str string) string {
+		return strings.ReplaceAll(str, old, new)
+	}
+}
+
+// ReplaceRegexp returns a function which replaces all matches of `re` with `replacement`
+// (which can have $1-style references to submatches).
+func ReplaceRegexp(re *regexp.Regexp, replacement string) func(string) string {
+	return func(str string) string {
+		return re.ReplaceAllString(str, replacement)
+	}
+}
+
+// ReplaceInPages fetches pages of the account, transforms them with given options,
+// and edits the changed ones with EditPage (unless `options.DryRun` is true).
+//
+// Only changed pages are returned. Failures of editing do not stop others;
+// they are returned in changes (and as a joined error).
+func ReplaceInPages(client API, options ReplaceOptions) (changes []PageChange, err error) {
+	paths := options.Paths
+	if len(paths) == 0 {
+		var pages []Page
+		if pages, err = ListAllPages(client); err != nil {
+			return nil, fmt.Errorf("failed to list pages: %w", err)
+		}
+		for _, page := range pages {
+			paths = append(paths, page.Path)
+		}
+	}
+
+	errs := []error{}
+	for _, path := range paths {
+		var old Page
+		if old, err = client.GetPage(path, true); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get page '%s': %w", path, err))
+			continue
+		}
+
+		page := old
+		var changed bool
+		page.Content, changed = TransformNodes(old.Content, options.Text, options.URL)
+		if options.Text != nil {
+			if page.Title = options.Text(old.Title); page.Title != old.Title {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		change := PageChange{
+			Path:  path,
+			Title: page.Title,
+			Diff:  DiffPages(old, page),
+		}
+		if !options.DryRun {
+			if _, change.Err = client.EditPage(path, page.Title, page.Content, page.AuthorName, page.AuthorURL, false); change.Err == nil {
+				change.Edited = true
+			} else {
+				errs = append(errs, fmt.Errorf("failed to edit page '%s': %w", path, change.Err))
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// TransformNodes returns nodes whose texts are transformed with `text`,
+// and href/src attributes with `url` (both can be nil), and whether any of them was changed.
+func TransformNodes(nodes []Node, text, url func(string) string) (transformed []Node, changed bool) {
+	transformed = []Node{}
+
+	for _, node := range nodes {
+		switch n := normalizeNode(node).(type) {
+		case string:
+			if text != nil {
+				if str := text(n); str != n {
+					n = str
+					changed = true
+				}
+			}
+			transformed = append(transformed, n)
+		case NodeElement:
+			var childrenChanged bool
+			n.Children, childrenChanged = TransformNodes(n.Children, text, url)
+			changed = changed || childrenChanged
+
+			if url != nil && len(n.Attrs) > 0 {
+				attrs := map[string]string{}
+				for key, value := range n.Attrs {
+					if key == "href" || key == "src" {
+						if rewritten := url(value); rewritten != value {
+							value = rewritten
+							changed = true
+						}
+					}
+					attrs[key] = value
+				}
+				n.Attrs = attrs
+			}
+
+			transformed = append(transformed, n)
+		default:
+			transformed = append(transformed, node)
+		}
+	}
+
+	return transformed, changed
+}
@@ -0,0 +1,53 @@
+package telegraph_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+	"github.com/meinside/telegraph-go/telegraphtest"
+)
+
+func TestReplaceInPages(t *testing.T) {
+	fake := telegraphtest.NewFake()
+	fake.AddPage(telegraph.Page{Path: "Changed-01-01", Title: "About OldName", Content: []telegraph.Node{
+		telegraph.P("See ", telegraph.A("https://old.example.com/docs", "docs")),
+	}})
+	fake.AddPage(telegraph.Page{Path: "Unchanged-01-02", Title: "Other", Content: []telegraph.Node{telegraph.P("nothing")}})
+
+	options := telegraph.ReplaceOptions{
+		Text:   telegraph.ReplaceString("OldName", "NewName"),
+		URL:    telegraph.ReplaceRegexp(regexp.MustCompile(`^https://old\.example\.com`), "https://new.example.com"),
+		DryRun: true,
+	}
+
+	// dry run
+	changes, err := telegraph.ReplaceInPages(fake, options)
+	if err != nil || len(changes) != 1 || changes[0].Edited {
+		t.Fatalf("unexpected changes: %#+v, %v", changes, err)
+	}
+	expected := `-title: About OldName
+-<p>See <a href="https://old.example.com/docs">docs</a></p>
++title: About NewName
++<p>See <a href="https://new.example.com/docs">docs</a></p>
+`
+	if changes[0].Diff != expected {
+		t.Errorf("unexpected diff:\n%s", changes[0].Diff)
+	}
+	if len(fake.CallsTo(telegraphtest.MethodEditPage)) != 0 {
+		t.Errorf("pages should not be edited in dry runs")
+	}
+
+	// commit
+	options.DryRun = false
+	if changes, err = telegraph.ReplaceInPages(fake, options); err != nil || len(changes) != 1 || !changes[0].Edited {
+		t.Fatalf("unexpected changes: %#+v, %v", changes, err)
+	}
+	if calls := fake.CallsTo(telegraphtest.MethodEditPage); len(calls) != 1 {
+		t.Errorf("only changed pages should be edited: %#+v", calls)
+	}
+	if page, _ := fake.Page("Changed-01-01"); page.Title != "About NewName" || !strings.Contains(telegraph.RenderHTML(page.Content), "https://new.example.com/docs") {
+		t.Errorf("unexpected page: %#+v", page)
+	}
+}
@@ -0,0 +1,125 @@
+package telegraph
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Retiring pages
+//
+// Telegraph API cannot delete pages, so they are retired instead:
+// the current page is saved in the client's RevisionStore, and replaced with a tombstone.
+// Retired pages can be restored with UnretirePage.
+
+// Tombstone is the content of a retired page.
+type Tombstone struct {
+	Title        string // title of the retired page (default: the original title)
+	Notice       string // notice of the retired page (default: "This page has been removed.")
+	RedirectURL  string // url to be linked from the notice (optional)
+	RedirectText string // text of the link (default: RedirectURL)
+}
+
+// nodes of the tombstone
+func (t Tombstone) nodes() []Node {
+	notice := t.Notice
+	if notice == "" {
+		notice = "This page has been removed."
+	}
+	nodes := []Node{NodeElement{Tag: "p", Children: []Node{notice}}}
+
+	if t.RedirectURL != "" {
+		text := t.RedirectText
+		if text == "" {
+			text = t.RedirectURL
+		}
+		nodes = append(nodes, NodeElement{Tag: "p", Children: []Node{
+			NodeElement{Tag: "a", Attrs: map[string]string{"href": t.RedirectURL}, Children: []Node{text}},
+		}})
+	}
+
+	return nodes
+}
+
+// RetirePage saves the current page at given path as a revision, and replaces it with given tombstone.
+//
+// The retirement is recorded in the client's RevisionStore only after the tombstone is written,
+// so a failed RetirePage can be retried.
+//
+// The client should have a RevisionStore (see WithRevisionStore).
+func (c *Client) RetirePage(path string, tombstone Tombstone) (page Page, err error) {
+	if c.revisions == nil {
+		return page, ErrNoRevisionStore
+	}
+
+	if _, retired, err := c.retirement(path); err != nil {
+		return page, err
+	} else if retired {
+		return page, fmt.Errorf("page '%s' is already retired", path)
+	}
+
+	// saved as an edit first, so that the original page is kept even when recording the retirement fails
+	var current Page
+	if current, err = c.snapshotPage(path, RevisionReasonEdit); err != nil {
+		return page, err
+	}
+
+	title := tombstone.Title
+	if title == "" {
+		title = current.Title
+	}
+
+	if page, err = c.editPage("", path, title, tombstone.nodes(), current.AuthorName, current.AuthorURL, false); err == nil {
+		err = c.saveRevision(path, RevisionReasonRetire, current)
+	}
+
+	return page, err
+}
+
+// UnretirePage restores the page at given path to the revision saved by the last RetirePage.
+//
+// If it is not retired, an error wrapping ErrRevisionNotFound is returned.
+func (c *Client) UnretirePage(path string) (page Page, err error) {
+	if c.revisions == nil {
+		return page, ErrNoRevisionStore
+	}
+
+	var revision Revision
+	var retired bool
+	if revision, retired, err = c.retirement(path); err != nil {
+		return page, err
+	} else if !retired {
+		return page, fmt.Errorf("page '%s' is not retired: %w", path, ErrRevisionNotFound)
+	}
+
+	var current Page
+	if current, err = c.snapshotPage(path, RevisionReasonEdit); err != nil {
+		return page, err
+	}
+
+	original := revision.Page
+	if page, err = c.editPage("", path, original.Title, original.Content, original.AuthorName, original.AuthorURL, false); err == nil {
+		err = c.saveRevision(path, RevisionReasonUnretire, current)
+	}
+
+	return page, err
+}
+
+// find the revision saved by RetirePage, if the page at given path is retired and not unretired yet
+//
+// (revisions saved by edits after retiring do not matter)
+func (c *Client) retirement(path string) (revision Revision, retired bool, err error) {
+	var revisions []Revision
+	if revisions, err = c.revisions.ListRevisions(path); err != nil {
+		return revision, false, err
+	}
+	for _, revision := range slices.Backward(revisions) {
+		switch revision.Reason {
+		case RevisionReasonRetire:
+			return revision, true, nil
+		case RevisionReasonUnretire:
+			return Revision{}, false, nil
+		}
+	}
+
+	return Revision{}, false, nil
+}
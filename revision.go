@@ -0,0 +1,328 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Page revision history
+//
+// Telegraph does not keep revisions of pages, so when a RevisionStore is set with WithRevisionStore,
+// the current page is fetched and saved as a revision before each EditPage.
+// Saved revisions can be listed, compared, and restored.
+
+// ErrRevisionNotFound is returned when a revision does not exist.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// ErrNoRevisionStore is returned when the client has no RevisionStore (see WithRevisionStore).
+var ErrNoRevisionStore = errors.New("no revision store")
+
+// RevisionReason is the reason why a revision was saved.
+type RevisionReason string
+
+// RevisionReason constants
+const (
+	RevisionReasonEdit     RevisionReason = "edit"     // saved before EditPage
+	RevisionReasonRetire   RevisionReason = "retire"   // saved before RetirePage
+	RevisionReasonUnretire RevisionReason = "unretire" // saved before UnretirePage
+)
+
+// Revision is a saved snapshot of a page.
+type Revision struct {
+	ID        string         `json:"id"`
+	Path      string         `json:"path"`
+	CreatedAt time.Time      `json:"created_at"`
+	Reason    RevisionReason `json:"reason,omitempty"`
+	Page      Page           `json:"page"` // with content
+}
+
+// RevisionStore is an interface for storing revisions.
+type RevisionStore interface {
+	// SaveRevision stores given revision.
+	SaveRevision(revision Revision) error
+
+	// ListRevisions returns revisions of given path, the oldest first.
+	ListRevisions(path string) ([]Revision, error)
+
+	// GetRevision returns a revision, or ErrRevisionNotFound if it does not exist.
+	GetRevision(path, id string) (Revision, error)
+}
+
+// WithRevisionStore sets a RevisionStore for saving the current page before each EditPage.
+func WithRevisionStore(store RevisionStore) ClientOption {
+	return func(c *Client) {
+		c.revisions = store
+	}
+}
+
+// Revisions returns saved revisions of the page at given path, the oldest first.
+func (c *Client) Revisions(path string) ([]Revision, error) {
+	if c.revisions == nil {
+		return nil, ErrNoRevisionStore
+	}
+
+	return c.revisions.ListRevisions(path)
+}
+
+// RestoreRevision restores the page at given path to a saved revision with EditPage.
+//
+// (the current page is also saved as a new revision before it is restored)
+func (c *Client) RestoreRevision(path, id string) (page Page, err error) {
+	if c.revisions == nil {
+		return page, ErrNoRevisionStore
+	}
+
+	var revision Revision
+	if revision, err = c.revisions.GetRevision(path, id); err == nil {
+		return c.EditPage(path, revision.Page.Title, revision.Page.Content, revision.Page.AuthorName, revision.Page.AuthorURL, false)
+	}
+
+	return page, err
+}
+
+// save the current page at given path as a revision, and return it (no-op without a revision store)
+func (c *Client) snapshotPage(path string, reason RevisionReason) (current Page, err error) {
+	if c == nil || c.revisions == nil {
+		return current, nil
+	}
+
+	// not through the cache, for fetching the latest one
+	if current, err = request[Page](c, "getPage", path, map[string]any{"return_content": true}); err != nil {
+		return current, fmt.Errorf("failed to fetch page '%s' for saving its revision: %w", path, err)
+	}
+	current.Content = normalizeNodes(current.Content) // map[string]any => NodeElement
+
+	return current, c.saveRevision(path, reason, current)
+}
+
+// save given page as a revision of the page at given path
+func (c *Client) saveRevision(path string, reason RevisionReason, page Page) error {
+	now := time.Now()
+	if err := c.revisions.SaveRevision(Revision{
+		ID:        fmt.Sprintf("%d", now.UnixNano()),
+		Path:      path,
+		CreatedAt: now,
+		Reason:    reason,
+		Page:      page,
+	}); err != nil {
+		return fmt.Errorf("failed to save revision of page '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+////////////////
+// diffs
+
+// DiffPages returns a line-based diff of two pages' titles, authors, and contents.
+//
+// Lines are prefixed with "-" (only in old), "+" (only in new), or " " (in both).
+func DiffPages(old, new Page) string {
+	return diffLines(pageLines(old), pageLines(new))
+}
+
+// DiffRevisions returns a line-based diff of two revisions.
+func DiffRevisions(old, new Revision) string {
+	return fmt.Sprintf("--- %s (%s)\n+++ %s (%s)\n%s",
+		old.ID, old.CreatedAt.Format(time.RFC3339),
+		new.ID, new.CreatedAt.Format(time.RFC3339),
+		DiffPages(old.Page, new.Page))
+}
+
+// lines of a page for diffs
+func pageLines(page Page) []string {
+	lines := []string{"title: " + page.Title}
+	if page.AuthorName != "" || page.AuthorURL != "" {
+		lines = append(lines, fmt.Sprintf("author: %s <%s>", page.AuthorName, page.AuthorURL))
+	}
+
+	for _, node := range page.Content {
+		if str, ok := node.(string); ok && strings.TrimSpace(str) == "" {
+			continue
+		}
+		for line := range strings.SplitSeq(RenderHTML([]Node{node}), "\n") {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// diff two lists of lines with the longest common subsequence
+func diffLines(a, b []string) string {
+	// lengths of LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j >= len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("-" + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+" + b[j] + "\n")
+			j++
+		}
+	}
+
+	return sb.String()
+}
+
+////////////////
+// in-memory store
+
+// MemoryRevisionStore is a RevisionStore which keeps revisions in memory.
+type MemoryRevisionStore struct {
+	mu        sync.RWMutex
+	revisions map[string][]Revision // path => revisions
+}
+
+// NewMemoryRevisionStore creates a new MemoryRevisionStore.
+func NewMemoryRevisionStore() *MemoryRevisionStore {
+	return &MemoryRevisionStore{revisions: map[string][]Revision{}}
+}
+
+// SaveRevision stores given revision.
+func (s *MemoryRevisionStore) SaveRevision(revision Revision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revisions[revision.Path] = append(s.revisions[revision.Path], revision)
+	return nil
+}
+
+// ListRevisions returns revisions of given path.
+func (s *MemoryRevisionStore) ListRevisions(path string) ([]Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.revisions[path]), nil
+}
+
+// GetRevision returns a revision.
+func (s *MemoryRevisionStore) GetRevision(path, id string) (Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, revision := range s.revisions[path] {
+		if revision.ID == id {
+			return revision, nil
+		}
+	}
+	return Revision{}, ErrRevisionNotFound
+}
+
+////////////////
+// file store
+
+// FileRevisionStore is a RevisionStore which keeps revisions of each page in a JSON file in a directory.
+type FileRevisionStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileRevisionStore creates a new FileRevisionStore which saves files in `dir`.
+func NewFileRevisionStore(dir string) *FileRevisionStore {
+	return &FileRevisionStore{dir: dir}
+}
+
+// SaveRevision appends given revision to the file of its path.
+func (s *FileRevisionStore) SaveRevision(revision Revision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revisions, err := s.load(revision.Path)
+	if err != nil {
+		return err
+	}
+	revisions = append(revisions, revision)
+
+	var bytes []byte
+	if bytes, err = json.MarshalIndent(revisions, "", "  "); err == nil {
+		if err = os.MkdirAll(s.dir, 0755); err == nil {
+			file := s.file(revision.Path)
+			if err = os.WriteFile(file+".tmp", bytes, 0644); err == nil {
+				err = os.Rename(file+".tmp", file)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save revision of '%s': %s", revision.Path, err)
+	}
+
+	return nil
+}
+
+// ListRevisions returns revisions of given path.
+func (s *FileRevisionStore) ListRevisions(path string) ([]Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(path)
+}
+
+// GetRevision returns a revision.
+func (s *FileRevisionStore) GetRevision(path, id string) (Revision, error) {
+	revisions, err := s.ListRevisions(path)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	for _, revision := range revisions {
+		if revision.ID == id {
+			return revision, nil
+		}
+	}
+	return Revision{}, ErrRevisionNotFound
+}
+
+// load revisions of given path
+func (s *FileRevisionStore) load(path string) (revisions []Revision, err error) {
+	var bytes []byte
+	if bytes, err = os.ReadFile(s.file(path)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read revisions of '%s': %s", path, err)
+	}
+
+	if err = json.Unmarshal(bytes, &revisions); err != nil {
+		return nil, fmt.Errorf("failed to parse revisions of '%s': %s", path, err)
+	}
+	for i := range revisions {
+		revisions[i].Page.Content = normalizeNodes(revisions[i].Page.Content)
+	}
+
+	return revisions, nil
+}
+
+// file path for revisions of given page path
+func (s *FileRevisionStore) file(path string) string {
+	return filepath.Join(s.dir, url.PathEscape(path)+".json")
+}
@@ -0,0 +1,159 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRevisions(t *testing.T) {
+	transport := &cannedTransport{responses: []string{
+		// getPage for snapshot
+		`{"ok":true,"result":{"path":"Sample","title":"Original","content":[{"tag":"p","children":["original"]}]}}`,
+		// editPage
+		`{"ok":true,"result":{"path":"Sample","title":"Edited"}}`,
+		// getPage for snapshot before restoring
+		`{"ok":true,"result":{"path":"Sample","title":"Edited","content":[{"tag":"p","children":["edited"]}]}}`,
+		// editPage for restoring
+		`{"ok":true,"result":{"path":"Sample","title":"Original"}}`,
+	}}
+	client := NewClient("token", WithRevisionStore(NewFileRevisionStore(t.TempDir())), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if _, err := client.EditPage("Sample", "Edited", []Node{NewNodeWithElement("p", nil, []Node{"edited"})}, "", "", false); err != nil {
+		t.Fatalf("failed to edit page: %s", err)
+	}
+
+	revisions, err := client.Revisions("Sample")
+	if err != nil || len(revisions) != 1 || revisions[0].Page.Title != "Original" {
+		t.Fatalf("unexpected revisions: %#+v, %v", revisions, err)
+	}
+	if _, ok := revisions[0].Page.Content[0].(NodeElement); !ok {
+		t.Errorf("content of revision was not decoded into NodeElement: %#+v", revisions[0].Page.Content)
+	}
+
+	if _, err := client.RestoreRevision("Sample", revisions[0].ID); err != nil {
+		t.Fatalf("failed to restore revision: %s", err)
+	}
+	form, _ := url.ParseQuery(transport.bodies[len(transport.bodies)-1])
+	if form.Get("title") != "Original" || form.Get("content") != `[{"tag":"p","children":["original"]}]` {
+		t.Errorf("unexpected restoring request: %#+v", form)
+	}
+	if revisions, _ := client.Revisions("Sample"); len(revisions) != 2 {
+		t.Errorf("current page should be saved before restoring: %#+v", revisions)
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	var old, new Revision
+	_ = json.Unmarshal([]byte(`{"id":"1","page":{"title":"Title","content":[{"tag":"p","children":["same"]},{"tag":"p","children":["old"]}]}}`), &old)
+	_ = json.Unmarshal([]byte(`{"id":"2","page":{"title":"Title","content":[{"tag":"p","children":["same"]},{"tag":"p","children":["new"]}]}}`), &new)
+	old.CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	new.CreatedAt = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	expected := `--- 1 (2024-01-01T00:00:00Z)
++++ 2 (2024-01-02T00:00:00Z)
+ title: Title
+ <p>same</p>
+-<p>old</p>
++<p>new</p>
+`
+	if diff := DiffRevisions(old, new); diff != expected {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+func TestRetirePage(t *testing.T) {
+	transport := &cannedTransport{responses: []string{
+		// getPage for snapshot
+		`{"ok":true,"result":{"path":"Sample","title":"Original","author_name":"Author","content":[{"tag":"p","children":["original"]}]}}`,
+		// editPage for tombstone
+		`{"ok":true,"result":{"path":"Sample","title":"Removed"}}`,
+		// getPage for snapshot before editing the tombstone
+		`{"ok":true,"result":{"path":"Sample","title":"Removed","content":[{"tag":"p","children":["gone"]}]}}`,
+		// editPage for editing the tombstone
+		`{"ok":true,"result":{"path":"Sample","title":"Removed"}}`,
+		// getPage for snapshot before unretiring
+		`{"ok":true,"result":{"path":"Sample","title":"Removed","content":[{"tag":"p","children":["gone"]}]}}`,
+		// editPage for unretiring
+		`{"ok":true,"result":{"path":"Sample","title":"Original"}}`,
+	}}
+	client := NewClient("token", WithRevisionStore(NewMemoryRevisionStore()), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if _, err := client.UnretirePage("Sample"); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("unexpected error for a page never retired: %v", err)
+	}
+
+	if _, err := client.RetirePage("Sample", Tombstone{Title: "Removed", Notice: "gone", RedirectURL: "https://example.com"}); err != nil {
+		t.Fatalf("failed to retire page: %s", err)
+	}
+	form, _ := url.ParseQuery(transport.bodies[1])
+	if form.Get("title") != "Removed" || form.Get("author_name") != "Author" || form.Get("content") != `[{"tag":"p","children":["gone"]},{"tag":"p","children":[{"tag":"a","attrs":{"href":"https://example.com"},"children":["https://example.com"]}]}]` {
+		t.Errorf("unexpected retiring request: %#+v", form)
+	}
+	if _, err := client.EditPage("Sample", "Removed", []Node{"gone"}, "", "", false); err != nil {
+		t.Fatalf("failed to edit tombstone: %s", err)
+	}
+	if _, err := client.RetirePage("Sample", Tombstone{}); err == nil || transport.requests != 4 {
+		t.Errorf("retired page should not be retired again, even after its tombstone is edited")
+	}
+
+	if _, err := client.UnretirePage("Sample"); err != nil {
+		t.Fatalf("failed to unretire page: %s", err)
+	}
+	form, _ = url.ParseQuery(transport.bodies[5])
+	if form.Get("title") != "Original" || form.Get("content") != `[{"tag":"p","children":["original"]}]` {
+		t.Errorf("unexpected unretiring request: %#+v", form)
+	}
+	if _, err := client.UnretirePage("Sample"); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("unexpected error for an unretired page: %v", err)
+	}
+}
+
+func TestNoRevisionStore(t *testing.T) {
+	client := NewClient("token")
+
+	if _, err := client.Revisions("Sample"); !errors.Is(err, ErrNoRevisionStore) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := client.RetirePage("Sample", Tombstone{}); !errors.Is(err, ErrNoRevisionStore) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRetirePageFailures(t *testing.T) {
+	transport := &cannedTransport{responses: []string{
+		// getPage for snapshot
+		`{"ok":true,"result":{"path":"Sample","title":"Original","content":[{"tag":"p","children":["original"]}]}}`,
+		// editPage for tombstone (failed)
+		`{"ok":false,"error":"CONTENT_TOO_BIG"}`,
+		// getPage for snapshot (retried)
+		`{"ok":true,"result":{"path":"Sample","title":"Original","content":[{"tag":"p","children":["original"]}]}}`,
+		// editPage for tombstone
+		`{"ok":true,"result":{"path":"Sample","title":"Original"}}`,
+		// getPage for snapshot before unretiring
+		`{"ok":true,"result":{"path":"Sample","title":"Original","content":[{"tag":"p","children":["removed"]}]}}`,
+		// editPage for unretiring (failed)
+		`{"ok":false,"error":"CONTENT_TOO_BIG"}`,
+	}}
+	client := NewClient("token", WithRevisionStore(NewMemoryRevisionStore()), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if _, err := client.RetirePage("Sample", Tombstone{}); err == nil {
+		t.Fatalf("retiring should fail")
+	}
+	if _, retired, _ := client.retirement("Sample"); retired {
+		t.Errorf("page should not be retired after a failed retiring")
+	}
+	if _, err := client.RetirePage("Sample", Tombstone{}); err != nil {
+		t.Fatalf("retrying a failed retiring should succeed: %s", err)
+	}
+
+	if _, err := client.UnretirePage("Sample"); err == nil {
+		t.Fatalf("unretiring should fail")
+	}
+	if revision, retired, _ := client.retirement("Sample"); !retired || NodesText(revision.Page.Content) != "original" {
+		t.Errorf("page should be still retired after a failed unretiring: %#+v", revision)
+	}
+}
@@ -29,7 +29,7 @@ func main() {
 	if client, err := telegraph.Create("telegraph-test", "Telegraph Test", ""); err == nil {
 		log.Printf("> Created client: %#+v", client)
 
-		savedAccessToken = client.AccessToken
+		savedAccessToken = client.AccessToken()
 
 		// GetAccountInfo
 		if account, err := client.GetAccountInfo(nil); err == nil {
@@ -0,0 +1,87 @@
+package telegraph
+
+import (
+	"slices"
+	"strings"
+)
+
+// Sanitizing nodes
+//
+// Telegraph accepts only a few tags and attributes, and silently drops others.
+// Sanitizer converts nodes to ones which can be rendered by Telegraph.
+
+// AllowedTags is the list of tags supported by Telegraph.
+//
+// http://telegra.ph/api#NodeElement
+var AllowedTags = []string{
+	"a", "aside", "b", "blockquote", "br", "code", "em", "figcaption", "figure",
+	"h3", "h4", "hr", "i", "iframe", "img", "li", "ol", "p", "pre", "s", "strong", "u", "ul", "video",
+}
+
+// AllowedAttrs is the list of attributes supported by Telegraph.
+var AllowedAttrs = []string{"href", "src"}
+
+// tags replaced with supported ones
+var replacedTags = map[string]string{
+	"h1": "h3", "h2": "h3", "h5": "h4", "h6": "h4",
+	"del": "s", "strike": "s", "ins": "u", "q": "blockquote", "cite": "i", "var": "i", "kbd": "code", "samp": "code",
+}
+
+// tags dropped with their children
+var droppedTags = []string{"script", "style", "head", "title", "meta", "link", "noscript", "template"}
+
+// NewSanitizedNodesWithHTML creates new nodes with given HTML string, and sanitizes them with SanitizeNodes.
+func NewSanitizedNodesWithHTML(html string) ([]Node, error) {
+	nodes, err := NewNodesWithHTML(html)
+	if err != nil {
+		return nil, err
+	}
+
+	return SanitizeNodes(nodes), nil
+}
+
+// SanitizeNodes returns nodes which can be rendered by Telegraph:
+//
+// replaces some tags with similar supported ones (eg. h1 => h3, del => s),
+// removes unsupported tags while keeping their children (eg. div, span, font),
+// removes scripts and styles entirely, and removes unsupported attributes.
+func SanitizeNodes(nodes []Node) []Node {
+	sanitized := []Node{}
+
+	for _, node := range nodes {
+		switch n := normalizeNode(node).(type) {
+		case string:
+			sanitized = append(sanitized, n)
+		case NodeElement:
+			tag := strings.ToLower(n.Tag)
+			if replaced, exists := replacedTags[tag]; exists {
+				tag = replaced
+			}
+
+			switch {
+			case slices.Contains(droppedTags, tag):
+				continue
+			case slices.Contains(AllowedTags, tag):
+				var attrs map[string]string
+				for key, value := range n.Attrs {
+					if slices.Contains(AllowedAttrs, key) {
+						if attrs == nil {
+							attrs = map[string]string{}
+						}
+						attrs[key] = value
+					}
+				}
+
+				sanitized = append(sanitized, NodeElement{
+					Tag:      tag,
+					Attrs:    attrs,
+					Children: SanitizeNodes(n.Children),
+				})
+			default: // unwrap unsupported element
+				sanitized = append(sanitized, SanitizeNodes(n.Children)...)
+			}
+		}
+	}
+
+	return sanitized
+}
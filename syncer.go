@@ -0,0 +1,360 @@
+package telegraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Directory-to-account publishing
+//
+// Syncer mirrors Markdown and HTML files in a local directory to Telegraph pages.
+// Mappings from local files to page paths are kept in a manifest file,
+// which is saved after each successful operation, so interrupted syncs can be resumed.
+
+// SyncManifest is a mapping of local files to Telegraph pages.
+type SyncManifest struct {
+	Entries map[string]SyncEntry `json:"entries"` // key: slash-separated file path relative to the synced directory
+}
+
+// SyncEntry is a synced page in SyncManifest.
+type SyncEntry struct {
+	Path  string `json:"path"`  // path of the Telegraph page
+	URL   string `json:"url"`   // url of the Telegraph page
+	Title string `json:"title"` // title of the Telegraph page
+	Hash  string `json:"hash"`  // hash of the last published file
+}
+
+// SyncActionType is a type of SyncAction.
+type SyncActionType string
+
+// SyncActionType constants
+const (
+	SyncActionCreate    SyncActionType = "create"    // new file: will be created with `CreatePage`
+	SyncActionUpdate    SyncActionType = "update"    // modified file: will be edited with `EditPage`
+	SyncActionRename    SyncActionType = "rename"    // renamed (or moved) file: will be edited with `EditPage` only when its title was changed
+	SyncActionDelete    SyncActionType = "delete"    // deleted file: will be removed from the manifest (Telegraph API cannot delete pages)
+	SyncActionUnchanged SyncActionType = "unchanged" // not modified
+)
+
+// SyncAction is an action to be taken for syncing a file.
+type SyncAction struct {
+	Type    SyncActionType `json:"type"`
+	File    string         `json:"file"`               // slash-separated file path relative to the synced directory
+	OldFile string         `json:"old_file,omitempty"` // previous file path (only for renamed files)
+	Path    string         `json:"path,omitempty"`     // path of the Telegraph page (empty for new files)
+	Title   string         `json:"title,omitempty"`    // title of the page
+	Hash    string         `json:"hash,omitempty"`     // hash of the file
+}
+
+// SyncFailure is a failed SyncAction with its error.
+type SyncFailure struct {
+	Action SyncAction
+	Err    error
+}
+
+// SyncResult is the result of Syncer.Sync.
+type SyncResult struct {
+	Succeeded []SyncAction
+	Failed    []SyncFailure
+}
+
+// Syncer syncs files in a local directory with Telegraph pages.
+type Syncer struct {
+	client       API
+	dir          string
+	manifestPath string
+
+	// author of created/edited pages (optional)
+	AuthorName string
+	AuthorURL  string
+}
+
+// file extensions which can be synced
+var syncableExtensions = []string{".md", ".markdown", ".html", ".htm"}
+
+// NewSyncer creates a new Syncer which syncs files in `dir` with pages of given client.
+//
+// manifestPath: path to the manifest file (will be created if it does not exist)
+func NewSyncer(client API, dir, manifestPath string) *Syncer {
+	return &Syncer{
+		client:       client,
+		dir:          dir,
+		manifestPath: manifestPath,
+	}
+}
+
+// LoadManifest loads the manifest file, or returns an empty one if it does not exist yet.
+func (s *Syncer) LoadManifest() (manifest SyncManifest, err error) {
+	manifest.Entries = map[string]SyncEntry{}
+
+	var bytes []byte
+	if bytes, err = os.ReadFile(s.manifestPath); err == nil {
+		if err = json.Unmarshal(bytes, &manifest); err == nil {
+			if manifest.Entries == nil {
+				manifest.Entries = map[string]SyncEntry{}
+			}
+			return manifest, nil
+		}
+
+		return manifest, fmt.Errorf("failed to parse manifest '%s': %s", s.manifestPath, err)
+	} else if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+
+	return manifest, fmt.Errorf("failed to read manifest '%s': %s", s.manifestPath, err)
+}
+
+// save manifest file atomically
+func (s *Syncer) saveManifest(manifest SyncManifest) (err error) {
+	var bytes []byte
+	if bytes, err = json.MarshalIndent(manifest, "", "  "); err == nil {
+		tmp := s.manifestPath + ".tmp"
+		if err = os.WriteFile(tmp, bytes, 0644); err == nil {
+			if err = os.Rename(tmp, s.manifestPath); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to save manifest '%s': %s", s.manifestPath, err)
+}
+
+// Plan compares local files with the manifest, and returns actions to be taken.
+func (s *Syncer) Plan() (actions []SyncAction, err error) {
+	var manifest SyncManifest
+	if manifest, err = s.LoadManifest(); err != nil {
+		return nil, err
+	}
+
+	var files map[string]string // file => hash
+	if files, err = s.localFiles(); err != nil {
+		return nil, err
+	}
+
+	// entries whose files are gone (candidates of renamed files)
+	missing := map[string][]string{} // hash => files (files with the same contents share a hash)
+	for _, file := range sortedKeys(manifest.Entries) {
+		if _, exists := files[file]; !exists {
+			hash := manifest.Entries[file].Hash
+			missing[hash] = append(missing[hash], file)
+		}
+	}
+
+	for _, file := range sortedKeys(files) {
+		hash := files[file]
+
+		if entry, exists := manifest.Entries[file]; exists {
+			if entry.Hash == hash {
+				actions = append(actions, SyncAction{Type: SyncActionUnchanged, File: file, Path: entry.Path, Title: entry.Title, Hash: hash})
+			} else {
+				actions = append(actions, SyncAction{Type: SyncActionUpdate, File: file, Path: entry.Path, Hash: hash})
+			}
+		} else if oldFiles := missing[hash]; len(oldFiles) > 0 {
+			oldFile := oldFiles[0]
+			missing[hash] = oldFiles[1:]
+
+			entry := manifest.Entries[oldFile]
+			actions = append(actions, SyncAction{Type: SyncActionRename, File: file, OldFile: oldFile, Path: entry.Path, Hash: hash})
+		} else {
+			actions = append(actions, SyncAction{Type: SyncActionCreate, File: file, Hash: hash})
+		}
+	}
+
+	deleted := []string{}
+	for _, oldFiles := range missing {
+		deleted = append(deleted, oldFiles...)
+	}
+	slices.Sort(deleted)
+	for _, file := range deleted {
+		actions = append(actions, SyncAction{Type: SyncActionDelete, File: file, Path: manifest.Entries[file].Path})
+	}
+
+	return actions, nil
+}
+
+// Sync syncs local files with Telegraph pages.
+//
+// Failed actions do not stop the sync; they are returned in the result (and as a joined error),
+// and will be retried on the next sync.
+func (s *Syncer) Sync() (result SyncResult, err error) {
+	var manifest SyncManifest
+	if manifest, err = s.LoadManifest(); err != nil {
+		return result, err
+	}
+
+	var actions []SyncAction
+	if actions, err = s.Plan(); err != nil {
+		return result, err
+	}
+
+	errs := []error{}
+	for _, action := range actions {
+		if action, err = s.apply(manifest, action); err == nil {
+			err = s.saveManifest(manifest)
+		}
+
+		if err == nil {
+			result.Succeeded = append(result.Succeeded, action)
+		} else {
+			result.Failed = append(result.Failed, SyncFailure{Action: action, Err: err})
+			errs = append(errs, fmt.Errorf("failed to %s '%s': %w", action.Type, action.File, err))
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// apply given action and update the manifest
+func (s *Syncer) apply(manifest SyncManifest, action SyncAction) (SyncAction, error) {
+	switch action.Type {
+	case SyncActionUnchanged:
+		return action, nil
+	case SyncActionDelete:
+		delete(manifest.Entries, action.File)
+		return action, nil
+	}
+
+	title, content, err := s.readFile(action.File)
+	if err != nil {
+		return action, err
+	}
+	action.Title = title
+
+	var page Page
+	switch action.Type {
+	case SyncActionCreate:
+		page, err = s.client.CreatePage(title, s.AuthorName, s.AuthorURL, content, false)
+	case SyncActionUpdate:
+		page, err = s.client.EditPage(action.Path, title, content, s.AuthorName, s.AuthorURL, false)
+	case SyncActionRename:
+		entry := manifest.Entries[action.OldFile]
+		if entry.Title != title {
+			page, err = s.client.EditPage(action.Path, title, content, s.AuthorName, s.AuthorURL, false)
+		} else {
+			page = Page{Path: entry.Path, URL: entry.URL, Title: entry.Title}
+		}
+	default:
+		return action, fmt.Errorf("unknown sync action type: %s", action.Type)
+	}
+	if err != nil {
+		return action, err
+	}
+
+	if action.Type == SyncActionRename {
+		delete(manifest.Entries, action.OldFile)
+	}
+	action.Path = page.Path
+	manifest.Entries[action.File] = SyncEntry{
+		Path:  page.Path,
+		URL:   page.URL,
+		Title: title,
+		Hash:  action.Hash,
+	}
+
+	return action, nil
+}
+
+// list syncable files in the directory with their hashes
+func (s *Syncer) localFiles() (files map[string]string, err error) {
+	files = map[string]string{}
+
+	manifestPath, _ := filepath.Abs(s.manifestPath)
+
+	err = filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != s.dir { // skip hidden files and directories
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !slices.Contains(syncableExtensions, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); abs == manifestPath {
+			return nil
+		}
+
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(bytes)
+		files[filepath.ToSlash(rel)] = hex.EncodeToString(sum[:])
+
+		return nil
+	})
+
+	return files, err
+}
+
+// read a file and convert it to a title and nodes
+func (s *Syncer) readFile(file string) (title string, content []Node, err error) {
+	var bytes []byte
+	if bytes, err = os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(file))); err != nil {
+		return "", nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".md", ".markdown":
+		markdown := string(bytes)
+
+		// use the first level-1 heading as the title
+		lines := strings.SplitN(strings.TrimLeft(markdown, "\r\n\t "), "\n", 2)
+		if matches := mdHeadingRegex.FindStringSubmatch(lines[0]); matches != nil && matches[1] == "#" {
+			title = matches[2]
+			if len(lines) > 1 {
+				markdown = lines[1]
+			} else {
+				markdown = ""
+			}
+		}
+
+		content, err = NewNodesWithMarkdown(markdown)
+	default:
+		var doc *goquery.Document
+		if doc, err = goquery.NewDocumentFromReader(strings.NewReader(string(bytes))); err == nil {
+			// use <title> or the first <h1> as the title
+			if title = strings.TrimSpace(doc.Find("title").First().Text()); title == "" {
+				h1 := doc.Find("body h1").First()
+				title = strings.TrimSpace(h1.Text())
+				h1.Remove()
+			}
+
+			content = convertNodes(traverseNodes(doc.Find("body").Contents()), nil)
+		}
+	}
+
+	if title == "" { // fallback to the file name
+		title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	return title, content, err
+}
+
+// return sorted keys of given map
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,51 @@
+package telegraph_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	telegraph "github.com/meinside/telegraph-go"
+	"github.com/meinside/telegraph-go/telegraphtest"
+)
+
+func TestSyncerWithFake(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".manifest.json")
+	if err := os.WriteFile(filepath.Join(dir, "first.md"), []byte("# First\n\nfirst page"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "second.md"), []byte("# Second\n\nsecond page"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := telegraphtest.NewFake()
+	syncer := telegraph.NewSyncer(fake, dir, manifestPath)
+
+	// first sync fails partially
+	fake.SetError(telegraphtest.MethodCreatePage, os.ErrDeadlineExceeded)
+	if result, err := syncer.Sync(); err == nil || len(result.Failed) != 2 {
+		t.Fatalf("expected failures, but got: %#+v, %v", result, err)
+	}
+
+	// and is resumed
+	fake.SetError(telegraphtest.MethodCreatePage, nil)
+	if result, err := syncer.Sync(); err != nil || len(result.Succeeded) != 2 {
+		t.Fatalf("unexpected result: %#+v, %v", result, err)
+	}
+	if pages := fake.Pages(); len(pages) != 2 {
+		t.Fatalf("unexpected pages: %#+v", pages)
+	}
+
+	// modified file is edited, and unchanged one is not touched
+	if err := os.WriteFile(filepath.Join(dir, "first.md"), []byte("# First\n\nedited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fake.ResetCalls()
+	if _, err := syncer.Sync(); err != nil {
+		t.Fatalf("failed to sync: %s", err)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].Method != telegraphtest.MethodEditPage {
+		t.Errorf("unexpected calls: %#+v", calls)
+	}
+}
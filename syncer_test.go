@@ -0,0 +1,100 @@
+package telegraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncerPlan(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".manifest.json")
+
+	write := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("unchanged.md", "# Unchanged")
+	write("modified.md", "# Modified")
+	write("docs/renamed.html", "<h1>Renamed</h1>")
+	write("new.md", "# New")
+	write("ignored.txt", "not syncable")
+
+	syncer := NewSyncer(nil, dir, manifestPath)
+
+	files, err := syncer.localFiles()
+	if err != nil {
+		t.Fatalf("failed to list files: %s", err)
+	}
+
+	if err := syncer.saveManifest(SyncManifest{Entries: map[string]SyncEntry{
+		"unchanged.md":  {Path: "Unchanged-01-01", Hash: files["unchanged.md"]},
+		"modified.md":   {Path: "Modified-01-01", Hash: "outdated"},
+		"old/name.html": {Path: "Renamed-01-01", Hash: files["docs/renamed.html"]},
+		"deleted.md":    {Path: "Deleted-01-01", Hash: "deleted"},
+		"same.md":       {Path: "Same-01-01", Hash: "deleted"}, // same contents as deleted.md
+	}}); err != nil {
+		t.Fatalf("failed to save manifest: %s", err)
+	}
+
+	actions, err := syncer.Plan()
+	if err != nil {
+		t.Fatalf("failed to plan: %s", err)
+	}
+
+	expected := map[string]SyncAction{
+		"unchanged.md":      {Type: SyncActionUnchanged, Path: "Unchanged-01-01"},
+		"modified.md":       {Type: SyncActionUpdate, Path: "Modified-01-01"},
+		"docs/renamed.html": {Type: SyncActionRename, Path: "Renamed-01-01", OldFile: "old/name.html"},
+		"new.md":            {Type: SyncActionCreate},
+		"deleted.md":        {Type: SyncActionDelete, Path: "Deleted-01-01"},
+		"same.md":           {Type: SyncActionDelete, Path: "Same-01-01"},
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("expected %d actions, but got: %#+v", len(expected), actions)
+	}
+	for _, action := range actions {
+		if e, exists := expected[action.File]; !exists || e.Type != action.Type || e.Path != action.Path || e.OldFile != action.OldFile {
+			t.Errorf("unexpected action for '%s': %#+v", action.File, action)
+		}
+	}
+}
+
+func TestSyncerReadFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.md"), []byte("# Page title\n\nBody"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "untitled.html"), []byte("<p>Body</p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	syncer := NewSyncer(nil, dir, filepath.Join(dir, "manifest.json"))
+
+	if title, content, err := syncer.readFile("page.md"); err != nil || title != "Page title" || len(content) == 0 {
+		t.Errorf("unexpected result: %s, %#+v, %v", title, content, err)
+	}
+	if title, _, err := syncer.readFile("untitled.html"); err != nil || title != "untitled" {
+		t.Errorf("unexpected result: %s, %v", title, err)
+	}
+
+	for markdown, expected := range map[string]string{
+		"# Title #\n\nBody":  "Title",
+		"# #hashtag\n\nBody": "#hashtag",
+		"## Level 2\n\nBody": "heading",
+		"# C#\r\n\r\nBody":   "C#",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "heading.md"), []byte(markdown), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if title, _, err := syncer.readFile("heading.md"); err != nil || title != expected {
+			t.Errorf("unexpected title of %q: %s, %v", markdown, title, err)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package telegraph
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Table conversion
+//
+// Telegraph does not support tables, so tables in HTML (or Markdown) are converted to
+// aligned monospace texts, nested lists, or paragraphs.
+
+// TableStyle is a style of converted tables.
+type TableStyle string
+
+// TableStyle constants
+const (
+	TableStylePre        TableStyle = "pre"        // aligned monospace text in a <pre> element
+	TableStyleList       TableStyle = "list"       // a list of rows, with nested lists of labeled cells
+	TableStyleParagraphs TableStyle = "paragraphs" // one paragraph per row, with bold header labels
+)
+
+// TableOptions is the options for converting tables.
+type TableOptions struct {
+	Style TableStyle // (default: TableStylePre)
+
+	MaxWidth       int // maximum width of lines in TableStylePre, in monospace columns (0 = unlimited)
+	MaxColumnWidth int // maximum width of each column in TableStylePre (0 = unlimited)
+}
+
+// ConvertOption is an option for converting HTML or Markdown to nodes.
+type ConvertOption func(*convertOptions)
+
+// options for converting HTML or Markdown to nodes
+type convertOptions struct {
+	table TableOptions
+}
+
+// WithTableOptions sets options for converting tables.
+func WithTableOptions(options TableOptions) ConvertOption {
+	return func(o *convertOptions) {
+		o.table = options
+	}
+}
+
+// convert traversed nodes with given options: tables and embeds
+func convertNodes(nodes []Node, options []ConvertOption) []Node {
+	o := convertOptions{}
+	for _, option := range options {
+		option(&o)
+	}
+
+	return detectEmbeds(convertTables(nodes, o.table))
+}
+
+// a cell of a table
+type tableCell struct {
+	children []Node
+	text     string // whitespace-collapsed text
+	header   bool
+	align    string // "left", "center", or "right"
+}
+
+// convert table elements in given nodes
+func convertTables(nodes []Node, options TableOptions) []Node {
+	converted := []Node{}
+
+	for _, node := range nodes {
+		if element, ok := node.(NodeElement); ok {
+			if element.Tag == "table" {
+				converted = append(converted, convertTable(element, options)...)
+				continue
+			}
+
+			element.Children = convertTables(element.Children, options)
+			node = element
+		}
+		converted = append(converted, node)
+	}
+
+	return converted
+}
+
+// convert a table element
+func convertTable(table NodeElement, options TableOptions) []Node {
+	rows := tableRows(table.Children, false, options)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// header row
+	var header []tableCell
+	if !slices.ContainsFunc(rows[0], func(cell tableCell) bool { return !cell.header }) {
+		header, rows = rows[0], rows[1:]
+	}
+
+	switch options.Style {
+	case TableStyleList:
+		return tableAsList(header, rows)
+	case TableStyleParagraphs:
+		return tableAsParagraphs(header, rows)
+	default:
+		return tableAsPre(header, rows, options)
+	}
+}
+
+// collect rows of a table (excluding nested tables)
+func tableRows(nodes []Node, inHead bool, options TableOptions) (rows [][]tableCell) {
+	for _, node := range nodes {
+		element, ok := node.(NodeElement)
+		if !ok {
+			continue
+		}
+
+		switch element.Tag {
+		case "thead":
+			rows = append(rows, tableRows(element.Children, true, options)...)
+		case "tbody", "tfoot":
+			rows = append(rows, tableRows(element.Children, false, options)...)
+		case "tr":
+			row := []tableCell{}
+			for _, child := range element.Children {
+				if cell, ok := child.(NodeElement); ok && (cell.Tag == "td" || cell.Tag == "th") {
+					children := convertTables(cell.Children, options)
+					row = append(row, tableCell{
+						children: children,
+						text:     strings.Join(strings.Fields(NodesText(children)), " "),
+						header:   inHead || cell.Tag == "th",
+						align:    cellAlign(cell),
+					})
+				}
+			}
+			if len(row) > 0 {
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	return rows
+}
+
+// alignment of a cell from its `align` or `style` attribute
+func cellAlign(cell NodeElement) string {
+	align := strings.ToLower(cell.Attrs["align"])
+	if style := strings.ToLower(cell.Attrs["style"]); align == "" && strings.Contains(style, "text-align") {
+		for _, candidate := range []string{"center", "right", "left"} {
+			if strings.Contains(style, candidate) {
+				return candidate
+			}
+		}
+	}
+
+	return align
+}
+
+// convert a table to aligned text in <pre>
+func tableAsPre(header []tableCell, rows [][]tableCell, options TableOptions) []Node {
+	all := rows
+	if header != nil {
+		all = append([][]tableCell{header}, rows...)
+	}
+
+	// widths and alignments of columns
+	widths, aligns := []int{}, []string{}
+	for _, row := range all {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+				aligns = append(aligns, cell.align)
+			}
+			widths[i] = max(widths[i], displayWidth(cell.text))
+		}
+	}
+	if options.MaxColumnWidth > 0 {
+		for i := range widths {
+			widths[i] = min(widths[i], options.MaxColumnWidth)
+		}
+	}
+	if options.MaxWidth > 0 { // shrink the widest columns
+		for total(widths)+2*(len(widths)-1) > options.MaxWidth {
+			widest := 0
+			for i := range widths {
+				if widths[i] > widths[widest] {
+					widest = i
+				}
+			}
+			if widths[widest] <= 1 {
+				break
+			}
+			widths[widest]--
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(row []tableCell) {
+		var line strings.Builder
+		for i, width := range widths {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			text := ""
+			if i < len(row) {
+				text = row[i].text
+			}
+			line.WriteString(padText(truncateText(text, width), width, aligns[i]))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+
+	if header != nil {
+		writeRow(header)
+		separators := []string{}
+		for _, width := range widths {
+			separators = append(separators, strings.Repeat("-", width))
+		}
+		b.WriteString(strings.Join(separators, "  ") + "\n")
+	}
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return []Node{NodeElement{Tag: "pre", Children: []Node{strings.TrimSuffix(b.String(), "\n")}}}
+}
+
+// convert a table to a list of rows (the first cell of each row), with nested lists of the other cells
+func tableAsList(header []tableCell, rows [][]tableCell) []Node {
+	items := []Node{}
+	for _, row := range rows {
+		item := NodeElement{Tag: "li", Children: labeledCell(header, row, 0)}
+
+		nested := []Node{}
+		for i := 1; i < len(row); i++ {
+			nested = append(nested, NodeElement{Tag: "li", Children: labeledCell(header, row, i)})
+		}
+		if len(nested) > 0 {
+			item.Children = append(item.Children, NodeElement{Tag: "ul", Children: nested})
+		}
+
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	return []Node{NodeElement{Tag: "ul", Children: items}}
+}
+
+// convert a table to paragraphs of rows
+func tableAsParagraphs(header []tableCell, rows [][]tableCell) []Node {
+	paragraphs := []Node{}
+	for _, row := range rows {
+		children := []Node{}
+		for i := range row {
+			if i > 0 {
+				children = append(children, NodeElement{Tag: "br"})
+			}
+			children = append(children, labeledCell(header, row, i)...)
+		}
+		paragraphs = append(paragraphs, NodeElement{Tag: "p", Children: children})
+	}
+
+	return paragraphs
+}
+
+// children of a cell, labeled with its header in bold
+func labeledCell(header []tableCell, row []tableCell, i int) []Node {
+	if i < len(header) && header[i].text != "" {
+		return append([]Node{NodeElement{Tag: "b", Children: []Node{header[i].text + ":"}}, " "}, row[i].children...)
+	}
+
+	return row[i].children
+}
+
+// sum of given numbers
+func total(numbers []int) (sum int) {
+	for _, n := range numbers {
+		sum += n
+	}
+
+	return sum
+}
+
+// truncate a text to given display width, with an ellipsis
+func truncateText(text string, width int) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+
+	var b strings.Builder
+	w := 0
+	for _, r := range text {
+		if w+runeWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		w += runeWidth(r)
+	}
+	if width > 0 {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// pad a text to given display width with given alignment
+func padText(text string, width int, align string) string {
+	padding := max(width-displayWidth(text), 0)
+
+	switch align {
+	case "right":
+		return strings.Repeat(" ", padding) + text
+	case "center":
+		return strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2)
+	default:
+		return text + strings.Repeat(" ", padding)
+	}
+}
+
+// display width of a text in monospace columns
+func displayWidth(text string) (width int) {
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+
+	return width
+}
+
+// display width of a rune in monospace columns: 0 for combining marks, 2 for wide (eg. CJK) characters
+func runeWidth(r rune) int {
+	switch {
+	case r == utf8.RuneError || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) || unicode.IsControl(r):
+		return 0
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F, // CJK ... Yi
+		r >= 0xAC00 && r <= 0xD7A3,                // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF,                // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F,                // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60,                // fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F, // emoji
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+
+	return 1
+}
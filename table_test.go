@@ -0,0 +1,35 @@
+package telegraph
+
+import "testing"
+
+func TestTables(t *testing.T) {
+	markdown := `| Name | Score |
+|:-----|------:|
+| Alice | 10 |
+| 김철수 | 9 \| 8 |
+`
+
+	for _, test := range []struct {
+		options  TableOptions
+		expected string
+	}{
+		{TableOptions{}, "<pre>Name    Score\n------  -----\nAlice      10\n김철수  9 | 8</pre>\n"},
+		{TableOptions{MaxWidth: 10}, "<pre>Name  Sco…\n----  ----\nAli…    10\n김…   9 |…</pre>\n"},
+		{TableOptions{Style: TableStyleList}, "<ul><li><b>Name:</b> Alice<ul><li><b>Score:</b> 10</li></ul></li><li><b>Name:</b> 김철수<ul><li><b>Score:</b> 9 | 8</li></ul></li></ul>\n"},
+		{TableOptions{Style: TableStyleParagraphs}, "<p><b>Name:</b> Alice<br><b>Score:</b> 10</p><p><b>Name:</b> 김철수<br><b>Score:</b> 9 | 8</p>\n"},
+	} {
+		nodes, err := NewNodesWithMarkdown(markdown, WithTableOptions(test.options))
+		if err != nil {
+			t.Fatalf("failed to convert: %s", err)
+		}
+		if html := RenderHTML(nodes); html != test.expected {
+			t.Errorf("unexpected result with %+v:\n%q\nexpected:\n%q", test.options, html, test.expected)
+		}
+	}
+
+	// tables in HTML without header
+	nodes, _ := NewNodesWithHTML(`<table><tr><td>a</td><td><a href="https://example.com">b</a></td></tr></table>`, WithTableOptions(TableOptions{Style: TableStyleParagraphs}))
+	if html := RenderHTML(nodes); html != `<p>a<br><a href="https://example.com">b</a></p>` {
+		t.Errorf("unexpected result: %s", html)
+	}
+}
@@ -0,0 +1,483 @@
+package telegraph
+
+import (
+	"html"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Interoperability with Telegram Bot API
+
+////////////////
+// message entities
+
+// MessageEntity is a special entity in a Telegram message text (eg. bold, link, code).
+//
+// Offset and Length are in UTF-16 code units, as in Telegram Bot API.
+//
+// https://core.telegram.org/bots/api#messageentity
+type MessageEntity struct {
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	URL      string `json:"url,omitempty"`      // for "text_link"
+	Language string `json:"language,omitempty"` // for "pre"
+}
+
+// NewNodesWithMessageEntities creates new nodes with given Telegram message text and its entities.
+//
+// Paragraphs are separated by blank lines, and other line breaks become <br> elements.
+// Entities which are not supported by Telegraph (eg. spoiler, hashtag) are converted to plain text.
+func NewNodesWithMessageEntities(text string, entities []MessageEntity) []Node {
+	units := utf16.Encode([]rune(text))
+
+	// outer entities first
+	sorted := slices.Clone(entities)
+	slices.SortStableFunc(sorted, func(a, b MessageEntity) int {
+		if a.Offset != b.Offset {
+			return a.Offset - b.Offset
+		}
+		return b.Length - a.Length
+	})
+
+	return paragraphNodes(entityNodes(units, 0, len(units), sorted))
+}
+
+// build nodes of units[start:end] with given (sorted) entities
+func entityNodes(units []uint16, start, end int, entities []MessageEntity) []Node {
+	nodes := []Node{}
+
+	cursor := start
+	for i := 0; i < len(entities); {
+		entity := entities[i]
+		entityStart := min(max(entity.Offset, cursor), end)
+		entityEnd := min(max(entity.Offset+entity.Length, entityStart), end)
+
+		// entities nested in this one
+		j := i + 1
+		for j < len(entities) && entities[j].Offset < entityEnd {
+			j++
+		}
+
+		if entityStart > cursor {
+			nodes = append(nodes, string(utf16.Decode(units[cursor:entityStart])))
+		}
+		if entityEnd > entityStart {
+			text := string(utf16.Decode(units[entityStart:entityEnd]))
+			children := entityNodes(units, entityStart, entityEnd, entities[i+1:j])
+
+			nodes = append(nodes, entityNode(entity, text, children)...)
+		}
+
+		cursor = max(cursor, entityEnd)
+		i = j
+	}
+	if cursor < end {
+		nodes = append(nodes, string(utf16.Decode(units[cursor:end])))
+	}
+
+	return nodes
+}
+
+// convert an entity to nodes
+func entityNode(entity MessageEntity, text string, children []Node) []Node {
+	element := func(tag string, attrs map[string]string) []Node {
+		return []Node{NodeElement{Tag: tag, Attrs: attrs, Children: children}}
+	}
+
+	switch entity.Type {
+	case "bold":
+		return element("b", nil)
+	case "italic":
+		return element("i", nil)
+	case "underline":
+		return element("u", nil)
+	case "strikethrough":
+		return element("s", nil)
+	case "code":
+		return element("code", nil)
+	case "pre":
+		return element("pre", nil)
+	case "blockquote", "expandable_blockquote":
+		return element("blockquote", nil)
+	case "text_link":
+		return element("a", map[string]string{"href": entity.URL})
+	case "url":
+		href := text
+		if !strings.Contains(href, "://") {
+			href = "http://" + href
+		}
+		return element("a", map[string]string{"href": href})
+	case "email":
+		return element("a", map[string]string{"href": "mailto:" + text})
+	case "mention":
+		return element("a", map[string]string{"href": "https://t.me/" + strings.TrimPrefix(text, "@")})
+	default: // spoiler, hashtag, bot_command, ...
+		return children
+	}
+}
+
+// group top-level nodes into paragraphs, and replace line breaks with <br>
+func paragraphNodes(nodes []Node) []Node {
+	paragraphs := []Node{}
+
+	var current []Node
+	flush := func() {
+		if len(current) > 0 {
+			if str, ok := current[0].(string); ok {
+				current[0] = strings.TrimLeft(str, "\n")
+			}
+			if str, ok := current[len(current)-1].(string); ok {
+				current[len(current)-1] = strings.TrimRight(str, "\n")
+			}
+			if children := lineBreakNodes(current); len(children) > 0 {
+				paragraphs = append(paragraphs, NodeElement{Tag: "p", Children: children})
+			}
+		}
+		current = nil
+	}
+
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case string:
+			for i, part := range strings.Split(n, "\n\n") {
+				if i > 0 {
+					flush()
+				}
+				current = append(current, part)
+			}
+		case NodeElement:
+			if n.Tag == "pre" || n.Tag == "blockquote" {
+				flush()
+				paragraphs = append(paragraphs, lineBreakNodes([]Node{n})...)
+			} else {
+				current = append(current, n)
+			}
+		}
+	}
+	flush()
+
+	return paragraphs
+}
+
+// replace line breaks in strings with <br> (except in <pre>), and remove empty strings
+func lineBreakNodes(nodes []Node) []Node {
+	result := []Node{}
+
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case string:
+			for i, line := range strings.Split(n, "\n") {
+				if i > 0 {
+					result = append(result, NodeElement{Tag: "br"})
+				}
+				if line != "" {
+					result = append(result, line)
+				}
+			}
+		case NodeElement:
+			if n.Tag != "pre" {
+				n.Children = lineBreakNodes(n.Children)
+			}
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
+////////////////
+// Telegram HTML
+
+// TelegramMessageLimit is the maximum length of a Telegram message text.
+const TelegramMessageLimit = 4096
+
+// RenderTelegramHTML renders given nodes as HTML for Telegram Bot API's HTML parse mode,
+// split into chunks of at most TelegramMessageLimit characters (in UTF-16 code units, as Telegram counts them).
+func RenderTelegramHTML(nodes []Node) []string {
+	return RenderTelegramHTMLWithLimit(nodes, TelegramMessageLimit)
+}
+
+// RenderTelegramHTMLWithLimit renders given nodes as HTML for Telegram Bot API's HTML parse mode,
+// split into chunks of at most `limit` characters (in UTF-16 code units, as Telegram counts them).
+//
+// Only tags supported by Telegram (b, i, u, s, a, code, pre, blockquote, tg-spoiler) are used,
+// and other elements are degraded: headings become bold lines, lists become bullet texts,
+// and figures become links to their media.
+//
+// Lengths of chunks are counted with markups, so each chunk fits in a message.
+// When a chunk is split inside elements, they are closed at the end of the chunk,
+// and reopened at the start of the next one.
+func RenderTelegramHTMLWithLimit(nodes []Node, limit int) []string {
+	w := &telegramWriter{limit: max(limit, 1)}
+	for _, node := range nodes {
+		w.node(node)
+	}
+
+	return w.finish()
+}
+
+// tags supported by Telegram
+var telegramTags = map[string]string{
+	"b": "b", "strong": "b",
+	"i": "i", "em": "i",
+	"u": "u", "s": "s",
+	"code": "code", "pre": "pre",
+	"blockquote": "blockquote", "aside": "blockquote",
+	"tg-spoiler": "tg-spoiler",
+}
+
+// writer of Telegram HTML chunks
+type telegramWriter struct {
+	limit  int
+	chunks []string
+
+	b        strings.Builder
+	length   int      // length of the current chunk in UTF-16 code units
+	hasText  bool     // whether the current chunk has any text
+	open     []string // opening tags of open elements
+	tags     []string // tag names of open elements
+	closing  int      // length of closing tags of open elements
+	newlines int      // pending line breaks
+}
+
+// write a node
+func (w *telegramWriter) node(node Node) {
+	switch n := normalizeNode(node).(type) {
+	case string:
+		w.text(n)
+	case NodeElement:
+		switch n.Tag {
+		case "br":
+			w.text("\n")
+		case "p":
+			w.children(n.Children)
+			w.lineBreaks(2)
+		case "h3", "h4":
+			w.element("b", nil, n.Children)
+			w.lineBreaks(2)
+		case "hr":
+			w.lineBreaks(2)
+			w.text("――――――――")
+			w.lineBreaks(2)
+		case "ul", "ol":
+			index := 0
+			for _, child := range n.Children {
+				if item, ok := normalizeNode(child).(NodeElement); ok && item.Tag == "li" {
+					index++
+					w.lineBreaks(1)
+					if n.Tag == "ol" {
+						w.text(strconv.Itoa(index) + ". ")
+					} else {
+						w.text("• ")
+					}
+					w.children(item.Children)
+				}
+			}
+			w.lineBreaks(2)
+		case "figure":
+			var src string
+			var caption []Node
+			for _, child := range n.Children {
+				if element, ok := normalizeNode(child).(NodeElement); ok {
+					if element.Tag == "figcaption" {
+						caption = element.Children
+					} else if element.Attrs["src"] != "" {
+						src = element.Attrs["src"]
+					}
+				}
+			}
+			if src != "" {
+				if len(caption) == 0 {
+					caption = []Node{mediaURL(src)}
+				}
+				w.element("a", map[string]string{"href": mediaURL(src)}, caption)
+			} else {
+				w.children(caption)
+			}
+			w.lineBreaks(2)
+		case "img", "video", "iframe":
+			if src := n.Attrs["src"]; src != "" {
+				w.element("a", map[string]string{"href": mediaURL(src)}, []Node{mediaURL(src)})
+			}
+		case "a":
+			if href := n.Attrs["href"]; href != "" {
+				w.element("a", map[string]string{"href": absoluteURL(href)}, n.Children)
+			} else {
+				w.children(n.Children)
+			}
+		default:
+			if tag, exists := telegramTags[n.Tag]; exists {
+				if tag == "pre" || tag == "blockquote" {
+					w.lineBreaks(2)
+					w.element(tag, nil, n.Children)
+					w.lineBreaks(2)
+				} else {
+					w.element(tag, nil, n.Children)
+				}
+			} else { // unsupported element
+				w.children(n.Children)
+			}
+		}
+	}
+}
+
+// write nodes
+func (w *telegramWriter) children(nodes []Node) {
+	for _, node := range nodes {
+		w.node(node)
+	}
+}
+
+// write an element with given tag and attributes
+func (w *telegramWriter) element(tag string, attrs map[string]string, children []Node) {
+	opening := "<" + tag
+	if href, exists := attrs["href"]; exists {
+		opening += ` href="` + html.EscapeString(href) + `"`
+	}
+	opening += ">"
+	closing := "</" + tag + ">"
+
+	w.writePendingLineBreaks()
+	if w.length+utf16Length(opening)+utf16Length(closing)+w.closing >= w.limit {
+		w.flush()
+	}
+	w.b.WriteString(opening)
+	w.length += utf16Length(opening)
+	w.open = append(w.open, opening)
+	w.tags = append(w.tags, tag)
+	w.closing += utf16Length(closing)
+
+	w.children(children)
+
+	w.b.WriteString(closing)
+	w.length += utf16Length(closing)
+	w.open = w.open[:len(w.open)-1]
+	w.tags = w.tags[:len(w.tags)-1]
+	w.closing -= utf16Length(closing)
+}
+
+// write a text, splitting it into chunks if needed
+func (w *telegramWriter) text(text string) {
+	if text == "" {
+		return
+	}
+	w.writePendingLineBreaks()
+
+	for text != "" {
+		available := w.limit - w.length - w.closing
+
+		// longest prefix which fits, preferably ending with a whitespace
+		end, lastSpace, length := 0, 0, 0
+		for i, r := range text {
+			escaped := utf16Length(html.EscapeString(string(r)))
+			if length+escaped > available {
+				break
+			}
+			length += escaped
+			end = i + utf8.RuneLen(r)
+			if r == ' ' || r == '\n' {
+				lastSpace = end
+			}
+		}
+		if end < len(text) && lastSpace > 0 {
+			end = lastSpace
+		}
+
+		if end == 0 { // nothing fits
+			if w.hasText {
+				w.flush()
+				continue
+			}
+
+			// cannot fit even in an empty chunk (with too small a limit), so write at least one character
+			_, end = utf8.DecodeRuneInString(text)
+		}
+
+		escaped := html.EscapeString(text[:end])
+		w.b.WriteString(escaped)
+		w.length += utf16Length(escaped)
+		w.hasText = true
+		text = text[end:]
+
+		if text != "" {
+			w.flush()
+		}
+	}
+}
+
+// add pending line breaks (written before the next text)
+func (w *telegramWriter) lineBreaks(n int) {
+	w.newlines = max(w.newlines, n)
+}
+
+// write pending line breaks (not at the start of a chunk)
+func (w *telegramWriter) writePendingLineBreaks() {
+	newlines := w.newlines
+	w.newlines = 0
+
+	if w.hasText && newlines > 0 && w.length+newlines+w.closing < w.limit {
+		w.b.WriteString(strings.Repeat("\n", newlines))
+		w.length += newlines
+	}
+}
+
+// close open elements, and start a new chunk with them reopened
+func (w *telegramWriter) flush() {
+	if w.hasText {
+		for i := len(w.tags) - 1; i >= 0; i-- {
+			w.b.WriteString("</" + w.tags[i] + ">")
+		}
+		w.chunks = append(w.chunks, w.b.String())
+	}
+
+	w.b.Reset()
+	w.length = 0
+	w.hasText = false
+	for _, opening := range w.open {
+		w.b.WriteString(opening)
+		w.length += utf16Length(opening)
+	}
+}
+
+// return all chunks
+func (w *telegramWriter) finish() []string {
+	if w.hasText {
+		w.chunks = append(w.chunks, w.b.String())
+	}
+
+	return w.chunks
+}
+
+// length of given string in UTF-16 code units, as Telegram counts lengths of messages
+func utf16Length(s string) (length int) {
+	for _, r := range s {
+		length += utf16.RuneLen(r)
+	}
+
+	return length
+}
+
+// url of embedded media (eg. "/embed/youtube?url=..." => its original url)
+func mediaURL(src string) string {
+	if strings.HasPrefix(src, "/embed/") {
+		if u, err := url.Parse(src); err == nil && u.Query().Get("url") != "" {
+			return u.Query().Get("url")
+		}
+	}
+
+	return absoluteURL(src)
+}
+
+// absolute url of a Telegraph-relative one (eg. "/file/xxx.jpg")
+func absoluteURL(href string) string {
+	if strings.HasPrefix(href, "/") && !strings.HasPrefix(href, "//") {
+		return pageBaseURL + href
+	}
+
+	return href
+}
@@ -0,0 +1,63 @@
+package telegraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewNodesWithMessageEntities(t *testing.T) {
+	// "😀" is 2 UTF-16 code units
+	text := "😀 bold link\nsecond line\n\ncode\nblock\nafter"
+	entities := []MessageEntity{
+		{Type: "bold", Offset: 3, Length: 9},
+		{Type: "text_link", Offset: 8, Length: 4, URL: "https://example.com"},
+		{Type: "pre", Offset: 26, Length: 10},
+		{Type: "spoiler", Offset: 37, Length: 5},
+	}
+
+	expected := `<p>😀 <b>bold <a href="https://example.com">link</a></b><br>second line</p><pre>code
+block</pre><p>after</p>`
+	if html := RenderHTML(NewNodesWithMessageEntities(text, entities)); html != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", html, expected)
+	}
+}
+
+func TestRenderTelegramHTML(t *testing.T) {
+	nodes, _ := NewNodesWithHTML(`<h3>Title</h3><p>Hello <strong>world</strong> &amp; <a href="/Other-01-01">other</a></p><ul><li>one</li><li>two</li></ul><figure><img src="/file/a.png"><figcaption>image</figcaption></figure><figure><iframe src="/embed/youtube?url=https%3A%2F%2Fyoutu.be%2Fabc"></iframe></figure>`)
+
+	expected := `<b>Title</b>
+
+Hello <b>world</b> &amp; <a href="https://telegra.ph/Other-01-01">other</a>
+
+• one
+• two
+
+<a href="https://telegra.ph/file/a.png">image</a>
+
+<a href="https://youtu.be/abc">https://youtu.be/abc</a>`
+	if chunks := RenderTelegramHTML(nodes); len(chunks) != 1 || chunks[0] != expected {
+		t.Errorf("unexpected result:\n%#v\nexpected:\n%s", chunks, expected)
+	}
+
+	// split without breaking tags
+	chunks := RenderTelegramHTMLWithLimit([]Node{NewNodeWithElement("p", nil, []Node{NewNodeWithElement("b", nil, []Node{"aaaa bbbb cccc"})})}, 15)
+	expectedChunks := []string{"<b>aaaa </b>", "<b>bbbb </b>", "<b>cccc</b>"}
+	if len(chunks) != len(expectedChunks) {
+		t.Fatalf("unexpected chunks: %#v", chunks)
+	}
+	for i, chunk := range chunks {
+		if chunk != expectedChunks[i] {
+			t.Errorf("unexpected chunk: %s, expected: %s", chunk, expectedChunks[i])
+		}
+	}
+
+	// lengths are counted in UTF-16 code units
+	if chunks := RenderTelegramHTMLWithLimit([]Node{"😀😀😀"}, 4); len(chunks) != 2 || chunks[0] != "😀😀" || chunks[1] != "😀" {
+		t.Errorf("unexpected chunks: %#v", chunks)
+	}
+
+	// texts are not dropped even when they do not fit in an empty chunk
+	if chunks := RenderTelegramHTMLWithLimit([]Node{NewNodeWithElement("b", nil, []Node{"ab"})}, 5); strings.Join(chunks, "") != "<b>a</b><b>b</b>" {
+		t.Errorf("unexpected chunks: %#v", chunks)
+	}
+}
@@ -1,35 +1,286 @@
 package telegraph
 
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 // http://telegra.ph/api
 
 // constants
 const (
-	apiBaseURL = "https://api.telegra.ph"
+	apiBaseURL  = "https://api.telegra.ph"
+	pageBaseURL = "https://telegra.ph"
 )
 
 // Verbose flag for logging
 var Verbose bool // default: false
 
 // Client struct
+//
+// It is safe for concurrent use; its access token is updated atomically.
 type Client struct {
-	AccessToken string
+	mu          sync.RWMutex
+	accessToken string
+
+	tokenStore TokenStore
+
+	rotationHandlers      map[int]TokenRotationHandler
+	nextRotationHandlerID int
+
+	http            *http.Client
+	limiter         *RateLimiter
+	maxFloodRetries int
+	stats           clientStats
+
+	log   *slog.Logger
+	hooks []RequestHook
+
+	cache          Cache
+	cacheTTL       time.Duration
+	listGeneration atomic.Int64
+	flights        flightGroup
+
+	revisions RevisionStore
+}
+
+// ClientStats is the statistics of requests sent by a client.
+type ClientStats struct {
+	Requests      int64     `json:"requests"`
+	Errors        int64     `json:"errors"`
+	FloodWaits    int64     `json:"flood_waits"`
+	LastRequestAt time.Time `json:"last_request_at,omitempty"`
+}
+
+// counters for ClientStats
+type clientStats struct {
+	requests    atomic.Int64
+	errors      atomic.Int64
+	floodWaits  atomic.Int64
+	lastRequest atomic.Int64 // unix nano
+}
+
+// ClientOption is an option for creating a Client.
+type ClientOption func(*Client)
+
+// TokenRotationHandler is a function which is called when the client's access token is rotated.
+type TokenRotationHandler func(oldToken, newToken string)
+
+// WithTokenStore sets a TokenStore for reading and writing the client's access token.
+//
+// The store is updated when an account is created, loaded, or its access token is revoked.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Client) {
+		c.tokenStore = store
+	}
+}
+
+// WithHTTPClient sets a HTTP client for sending requests,
+// which can be shared by multiple clients for reusing connections.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.http = client
+	}
+}
+
+// WithRateLimiter sets a RateLimiter for limiting the rate of requests,
+// which can be shared by multiple clients for sharing one request budget.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithFloodWaitRetries sets the maximum number of retries on FLOOD_WAIT_X errors (default: 0).
+func WithFloodWaitRetries(retries int) ClientOption {
+	return func(c *Client) {
+		c.maxFloodRetries = retries
+	}
+}
+
+// NewClient creates a new Telegraph client with given access token, without verifying it.
+func NewClient(accessToken string, options ...ClientOption) *Client {
+	client := &Client{accessToken: accessToken}
+	for _, option := range options {
+		option(client)
+	}
+
+	return client
 }
 
 // Create creates a new Telegraph client.
-func Create(shortName, authorName, authorURL string) (client *Client, err error) {
+func Create(shortName, authorName, authorURL string, options ...ClientOption) (client *Client, err error) {
+	client = NewClient("", options...)
+
 	var account Account
 	if account, err = client.CreateAccount(shortName, authorName, authorURL); err == nil {
-		client = &Client{AccessToken: account.AccessToken}
+		client.accessToken = account.AccessToken
+
+		return client, client.saveAccessToken(account.AccessToken)
 	}
 
-	return client, err
+	return nil, err
 }
 
 // Load a Telegraph client with an existing access token.
-func Load(accessToken string) (client *Client, err error) {
-	client = &Client{AccessToken: accessToken}
+//
+// If `accessToken` is empty, it is read from the TokenStore given with WithTokenStore.
+func Load(accessToken string, options ...ClientOption) (client *Client, err error) {
+	client = NewClient(accessToken, options...)
 
-	_, err = client.GetAccountInfo(nil)
+	if accessToken == "" && client.tokenStore != nil {
+		var token string
+		if token, err = client.tokenStore.Load(); err != nil {
+			return client, err
+		}
+		client.SetAccessToken(token)
+	}
+
+	if _, err = client.GetAccountInfo(nil); err == nil && accessToken != "" {
+		err = client.saveAccessToken(accessToken)
+	}
 
 	return client, err
 }
+
+// LoadFromStore loads a Telegraph client with the access token in given TokenStore.
+func LoadFromStore(store TokenStore, options ...ClientOption) (client *Client, err error) {
+	return Load("", append([]ClientOption{WithTokenStore(store)}, options...)...)
+}
+
+// Stats returns the statistics of requests sent by the client.
+func (c *Client) Stats() ClientStats {
+	stats := ClientStats{
+		Requests:   c.stats.requests.Load(),
+		Errors:     c.stats.errors.Load(),
+		FloodWaits: c.stats.floodWaits.Load(),
+	}
+	if last := c.stats.lastRequest.Load(); last > 0 {
+		stats.LastRequestAt = time.Unix(0, last)
+	}
+
+	return stats
+}
+
+// AccessToken returns the current access token of the client.
+func (c *Client) AccessToken() string {
+	if c == nil {
+		return ""
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.accessToken
+}
+
+// SetAccessToken replaces the access token of the client,
+// and notifies the change to handlers registered with OnTokenRotated.
+//
+// Its TokenStore is not updated.
+func (c *Client) SetAccessToken(token string) {
+	c.mu.Lock()
+	oldToken := c.accessToken
+	c.accessToken = token
+	handlers := make([]TokenRotationHandler, 0, len(c.rotationHandlers))
+	for _, handler := range c.rotationHandlers {
+		handlers = append(handlers, handler)
+	}
+	c.mu.Unlock()
+
+	if oldToken != token {
+		for _, handler := range handlers {
+			handler(oldToken, token)
+		}
+	}
+}
+
+// OnTokenRotated registers a handler which will be called after the client's access token is changed
+// (eg. by RevokeAccessToken), and returns a function for unregistering it.
+func (c *Client) OnTokenRotated(handler TokenRotationHandler) (unsubscribe func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rotationHandlers == nil {
+		c.rotationHandlers = map[int]TokenRotationHandler{}
+	}
+	id := c.nextRotationHandlerID
+	c.nextRotationHandlerID++
+	c.rotationHandlers[id] = handler
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.rotationHandlers, id)
+	}
+}
+
+// replace the access token with a rotated one, and save it to the TokenStore
+func (c *Client) rotateAccessToken(token string) error {
+	c.SetAccessToken(token)
+
+	return c.saveAccessToken(token)
+}
+
+// save given access token to the client's TokenStore
+func (c *Client) saveAccessToken(token string) error {
+	if c.tokenStore != nil {
+		if err := c.tokenStore.Save(token); err != nil {
+			return fmt.Errorf("failed to save access token: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// HTTP client for sending requests
+func (c *Client) httpClient() *http.Client {
+	if c == nil || c.http == nil {
+		return defaultHTTPClient
+	}
+
+	return c.http
+}
+
+// rate limiter of the client (can be nil)
+func (c *Client) rateLimiter() *RateLimiter {
+	if c == nil {
+		return nil
+	}
+
+	return c.limiter
+}
+
+// maximum number of retries on FLOOD_WAIT_X errors
+func (c *Client) floodWaitRetries() int {
+	if c == nil {
+		return 0
+	}
+
+	return c.maxFloodRetries
+}
+
+// record the result of a request to the client's stats
+func (c *Client) recordRequest(err error) {
+	if c == nil {
+		return
+	}
+
+	s := &c.stats
+	s.requests.Add(1)
+	s.lastRequest.Store(time.Now().UnixNano())
+	if err != nil {
+		s.errors.Add(1)
+
+		var floodWait *FloodWaitError
+		if errors.As(err, &floodWait) {
+			s.floodWaits.Add(1)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package telegraph
 
 import (
+	"net/http"
+	"net/url"
 	"os"
 	"testing"
 )
@@ -21,7 +23,7 @@ func TestMethods(t *testing.T) {
 
 	// (1) XXX - create a new account,
 	if client, err := Create("telegraph-test", "Telegraph Test", ""); err == nil {
-		savedAccessToken = client.AccessToken
+		savedAccessToken = client.AccessToken()
 
 		// GetAccountInfo
 		if _, err := client.GetAccountInfo(nil); err != nil {
@@ -118,3 +120,45 @@ func TestMethods(t *testing.T) {
 		t.Errorf("failed to load client with existing access token: %s", err)
 	}
 }
+
+func TestTokenRotation(t *testing.T) {
+	store := NewMemoryTokenStore("")
+	transport := &cannedTransport{responses: []string{
+		`{"ok":true,"result":{"access_token":"new-token","auth_url":"https://edit.telegra.ph/auth/xxx"}}`,
+	}}
+	client := NewClient("old-token", WithTokenStore(store), WithHTTPClient(&http.Client{Transport: transport}))
+
+	rotated := make(chan [2]string, 1)
+	unsubscribe := client.OnTokenRotated(func(oldToken, newToken string) {
+		rotated <- [2]string{oldToken, newToken}
+	})
+
+	if account, err := client.RevokeAccessToken(); err != nil || account.AccessToken != "new-token" {
+		t.Fatalf("failed to revoke access token: %#+v, %v", account, err)
+	}
+	if form, _ := url.ParseQuery(transport.bodies[0]); form.Get("access_token") != "old-token" {
+		t.Errorf("old access token should be revoked: %#+v", form)
+	}
+	if token := client.AccessToken(); token != "new-token" {
+		t.Errorf("unexpected access token: %s", token)
+	}
+	if token, _ := store.Load(); token != "new-token" {
+		t.Errorf("unexpected access token in store: %s", token)
+	}
+	select {
+	case tokens := <-rotated:
+		if tokens != [2]string{"old-token", "new-token"} {
+			t.Errorf("unexpected rotation notification: %v", tokens)
+		}
+	default:
+		t.Errorf("rotation handler was not called")
+	}
+
+	unsubscribe()
+	client.SetAccessToken("newer-token")
+	select {
+	case tokens := <-rotated:
+		t.Errorf("unsubscribed handler was called: %v", tokens)
+	default:
+	}
+}
@@ -0,0 +1,392 @@
+// Package telegraphtest provides utilities for testing codes which use Telegraph API.
+package telegraphtest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+// method names of telegraph.API
+const (
+	MethodCreateAccount     = "CreateAccount"
+	MethodEditAccountInfo   = "EditAccountInfo"
+	MethodGetAccountInfo    = "GetAccountInfo"
+	MethodRevokeAccessToken = "RevokeAccessToken"
+	MethodCreatePage        = "CreatePage"
+	MethodEditPage          = "EditPage"
+	MethodGetPage           = "GetPage"
+	MethodGetPageList       = "GetPageList"
+	MethodGetViews          = "GetViews"
+)
+
+// Call is a recorded method call on Fake.
+type Call struct {
+	Method string
+	Args   []any
+	Err    error // returned error
+}
+
+// Fake is an in-memory implementation of telegraph.API.
+//
+// It keeps one account and its pages in memory, records all method calls,
+// and can be configured to return errors for specific methods.
+type Fake struct {
+	mu sync.Mutex
+
+	// base URL of page URLs (default: "https://telegra.ph")
+	BaseURL string
+
+	// function for the current time, used for generating page paths (default: time.Now)
+	Now func() time.Time
+
+	account telegraph.Account
+	pages   map[string]telegraph.Page // path => page
+	paths   []string                  // paths in the order of creation
+	views   map[string]int            // path => views
+	errors  map[string]error          // method => error
+	tokens  int
+	calls   []Call
+}
+
+// check if *Fake implements telegraph.API
+var _ telegraph.API = (*Fake)(nil)
+
+// NewFake creates a new Fake with an empty account.
+func NewFake() *Fake {
+	return &Fake{
+		BaseURL: "https://telegra.ph",
+		Now:     time.Now,
+		pages:   map[string]telegraph.Page{},
+		views:   map[string]int{},
+		errors:  map[string]error{},
+	}
+}
+
+////////////////
+// configurations
+
+// SetError makes given method return `err` (nil for clearing it).
+func (f *Fake) SetError(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.errors, method)
+	} else {
+		f.errors[method] = err
+	}
+}
+
+// SetAccount replaces the account.
+func (f *Fake) SetAccount(account telegraph.Account) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.account = account
+}
+
+// AddPage adds (or replaces) a page, generating its path and URL if empty, and returns it.
+func (f *Fake) AddPage(page telegraph.Page) telegraph.Page {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.putPage(page)
+}
+
+// SetViews sets the number of views of a page.
+func (f *Fake) SetViews(path string, views int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.views[path] = views
+}
+
+////////////////
+// assertions
+
+// Page returns the stored page at given path.
+func (f *Fake) Page(path string) (page telegraph.Page, exists bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	page, exists = f.pages[path]
+	return page, exists
+}
+
+// Pages returns all stored pages in the order of creation.
+func (f *Fake) Pages() (pages []telegraph.Page) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, path := range f.paths {
+		pages = append(pages, f.pages[path])
+	}
+	return pages
+}
+
+// Calls returns all recorded method calls.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// CallsTo returns recorded calls of given method.
+func (f *Fake) CallsTo(method string) (calls []Call) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls clears recorded method calls.
+func (f *Fake) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+////////////////
+// telegraph.API
+
+// CreateAccount replaces the account with a new one.
+func (f *Fake) CreateAccount(shortName, authorName, authorURL string) (account telegraph.Account, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodCreateAccount, shortName, authorName, authorURL); err == nil {
+		f.account = telegraph.Account{
+			ShortName:   shortName,
+			AuthorName:  authorName,
+			AuthorURL:   authorURL,
+			AccessToken: f.newToken(),
+		}
+		account = f.account
+	}
+
+	return account, err
+}
+
+// EditAccountInfo updates the account.
+func (f *Fake) EditAccountInfo(shortName, authorName, authorURL string) (account telegraph.Account, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodEditAccountInfo, shortName, authorName, authorURL); err == nil {
+		f.account.ShortName = shortName
+		f.account.AuthorName = authorName
+		f.account.AuthorURL = authorURL
+
+		account = f.account
+		account.AccessToken = ""
+	}
+
+	return account, err
+}
+
+// GetAccountInfo returns the account.
+func (f *Fake) GetAccountInfo(fields []string) (account telegraph.Account, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodGetAccountInfo, fields); err == nil {
+		account = f.account
+		account.AccessToken = ""
+		account.PageCount = len(f.pages)
+	}
+
+	return account, err
+}
+
+// RevokeAccessToken generates a new access token of the account.
+func (f *Fake) RevokeAccessToken() (account telegraph.Account, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodRevokeAccessToken); err == nil {
+		f.account.AccessToken = f.newToken()
+		account = f.account
+	}
+
+	return account, err
+}
+
+// CreatePage stores a new page.
+func (f *Fake) CreatePage(title, authorName, authorURL string, content []telegraph.Node, returnContent bool) (page telegraph.Page, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodCreatePage, title, authorName, authorURL, content, returnContent); err == nil {
+		page = f.putPage(telegraph.Page{
+			Title:      title,
+			AuthorName: authorName,
+			AuthorURL:  authorURL,
+			Content:    content,
+			CanEdit:    true,
+		})
+		page = withContent(page, returnContent)
+	}
+
+	return page, err
+}
+
+// EditPage updates a stored page.
+func (f *Fake) EditPage(path, title string, content []telegraph.Node, authorName, authorURL string, returnContent bool) (page telegraph.Page, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodEditPage, path, title, content, authorName, authorURL, returnContent); err == nil {
+		var exists bool
+		if page, exists = f.pages[path]; !exists {
+			err = &telegraph.APIError{Message: "PAGE_NOT_FOUND"}
+		} else {
+			page.Title = title
+			page.AuthorName = authorName
+			page.AuthorURL = authorURL
+			page.Content = content
+			page = f.putPage(page)
+			page = withContent(page, returnContent)
+		}
+	}
+
+	return page, err
+}
+
+// GetPage returns a stored page.
+func (f *Fake) GetPage(path string, returnContent bool) (page telegraph.Page, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodGetPage, path, returnContent); err == nil {
+		var exists bool
+		if page, exists = f.pages[path]; !exists {
+			err = &telegraph.APIError{Message: "PAGE_NOT_FOUND"}
+		} else {
+			page = withContent(page, returnContent)
+		}
+	}
+
+	return page, err
+}
+
+// GetPageList returns stored pages, the most recently created first.
+func (f *Fake) GetPageList(offset, limit int) (list telegraph.PageList, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodGetPageList, offset, limit); err == nil {
+		if limit < 0 {
+			limit = 50
+		}
+
+		list.TotalCount = len(f.paths)
+		list.Pages = []telegraph.Page{}
+		for i := len(f.paths) - 1 - offset; i >= 0 && len(list.Pages) < limit; i-- {
+			list.Pages = append(list.Pages, withContent(f.pages[f.paths[i]], false))
+		}
+	}
+
+	return list, err
+}
+
+// GetViews returns the number of views set with SetViews.
+func (f *Fake) GetViews(path string, year, month, day, hour int) (views telegraph.PageViews, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err = f.record(MethodGetViews, path, year, month, day, hour); err == nil {
+		if _, exists := f.pages[path]; !exists {
+			err = &telegraph.APIError{Message: "PAGE_NOT_FOUND"}
+		} else {
+			views.Views = f.views[path]
+		}
+	}
+
+	return views, err
+}
+
+////////////////
+// helpers
+
+// record a method call, and return the configured error
+func (f *Fake) record(method string, args ...any) error {
+	err := f.errors[method]
+	f.calls = append(f.calls, Call{Method: method, Args: args, Err: err})
+
+	return err
+}
+
+// generate a new access token
+func (f *Fake) newToken() string {
+	f.tokens++
+
+	return fmt.Sprintf("fake-access-token-%d", f.tokens)
+}
+
+// store given page, generating its path and URL if empty
+func (f *Fake) putPage(page telegraph.Page) telegraph.Page {
+	if page.Path == "" {
+		page.Path = f.newPath(page.Title)
+	}
+	if page.URL == "" {
+		page.URL = strings.TrimSuffix(f.BaseURL, "/") + "/" + page.Path
+	}
+	page.Views = f.views[page.Path]
+
+	if _, exists := f.pages[page.Path]; !exists {
+		f.paths = append(f.paths, page.Path)
+	}
+	f.pages[page.Path] = page
+
+	return page
+}
+
+// generate a new page path like Telegraph does (eg. "Title-12-31", "Title-12-31-2")
+func (f *Fake) newPath(title string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			if s := b.String(); len(s) > 0 && !strings.HasSuffix(s, "-") {
+				b.WriteRune('-')
+			}
+		}
+	}
+	now := time.Now
+	if f.Now != nil {
+		now = f.Now
+	}
+	base := fmt.Sprintf("%s-%s", strings.TrimSuffix(b.String(), "-"), now().Format("01-02"))
+
+	path := base
+	for i := 2; ; i++ {
+		if _, exists := f.pages[path]; !exists {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// return given page with or without its content
+func withContent(page telegraph.Page, returnContent bool) telegraph.Page {
+	if !returnContent {
+		page.Content = nil
+	}
+
+	return page
+}
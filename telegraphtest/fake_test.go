@@ -0,0 +1,57 @@
+package telegraphtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	telegraph "github.com/meinside/telegraph-go"
+)
+
+func TestFake(t *testing.T) {
+	fake := NewFake()
+	fake.Now = func() time.Time { return time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC) }
+
+	var api telegraph.API = fake
+
+	if account, err := api.CreateAccount("test", "Tester", ""); err != nil || account.AccessToken == "" {
+		t.Fatalf("unexpected account: %#+v, %v", account, err)
+	}
+
+	page, err := api.CreatePage("Hello world", "Tester", "", []telegraph.Node{"hi"}, false)
+	if err != nil || page.Path != "Hello-world-12-31" || page.URL != "https://telegra.ph/Hello-world-12-31" || page.Content != nil {
+		t.Fatalf("unexpected page: %#+v, %v", page, err)
+	}
+	if second, _ := api.CreatePage("Hello world", "", "", nil, false); second.Path != "Hello-world-12-31-2" {
+		t.Errorf("unexpected path of duplicated title: %s", second.Path)
+	}
+
+	if edited, err := api.EditPage(page.Path, "Edited", []telegraph.Node{"edited"}, "", "", true); err != nil || edited.Title != "Edited" || len(edited.Content) != 1 {
+		t.Errorf("unexpected edited page: %#+v, %v", edited, err)
+	}
+	var apiErr *telegraph.APIError
+	if _, err := api.GetPage("Not-Found", false); !errors.As(err, &apiErr) || apiErr.Message != "PAGE_NOT_FOUND" {
+		t.Errorf("should fail with PAGE_NOT_FOUND for unknown page: %v", err)
+	}
+
+	if list, err := api.GetPageList(0, 1); err != nil || list.TotalCount != 2 || len(list.Pages) != 1 || list.Pages[0].Path != "Hello-world-12-31-2" {
+		t.Errorf("unexpected page list: %#+v, %v", list, err)
+	}
+
+	fake.SetViews(page.Path, 7)
+	if views, err := api.GetViews(page.Path, 0, 0, 0, -1); err != nil || views.Views != 7 {
+		t.Errorf("unexpected views: %#+v, %v", views, err)
+	}
+
+	fake.SetError(MethodGetAccountInfo, errors.New("failure"))
+	if _, err := api.GetAccountInfo(nil); err == nil {
+		t.Errorf("should fail with configured error")
+	}
+
+	if calls := fake.CallsTo(MethodCreatePage); len(calls) != 2 || calls[0].Args[0] != "Hello world" {
+		t.Errorf("unexpected recorded calls: %#+v", calls)
+	}
+	if calls := fake.CallsTo(MethodGetAccountInfo); len(calls) != 1 || calls[0].Err == nil {
+		t.Errorf("unexpected recorded calls: %#+v", calls)
+	}
+}
@@ -0,0 +1,119 @@
+package telegraph
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+)
+
+// Page templates
+//
+// Template executes a Go template with data, and converts the resulting HTML to sanitized nodes.
+// Following functions are available in templates:
+//
+//	{{figure "https://example.com/image.png" "caption"}}  image with an optional caption
+//	{{video "https://example.com/video.mp4" "caption"}}   video with an optional caption
+//	{{embed "youtube" "https://youtu.be/..." "caption"}}  embedded youtube/vimeo/twitter/telegram content
+//	{{aside "text"}}                                       aside block
+
+// Template is a template which produces nodes.
+type Template struct {
+	executor interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// NewHTMLTemplate parses given text as a html/template (data is escaped contextually).
+func NewHTMLTemplate(name, text string) (*Template, error) {
+	funcs := htmltemplate.FuncMap{}
+	for key, fn := range templateFuncs {
+		funcs[key] = func(args ...string) htmltemplate.HTML {
+			return htmltemplate.HTML(fn(args...))
+		}
+	}
+
+	t, err := htmltemplate.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{executor: t}, nil
+}
+
+// NewTextTemplate parses given text as a text/template (data is NOT escaped).
+func NewTextTemplate(name, text string) (*Template, error) {
+	funcs := texttemplate.FuncMap{}
+	for key, fn := range templateFuncs {
+		funcs[key] = fn
+	}
+
+	t, err := texttemplate.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{executor: t}, nil
+}
+
+// Execute executes the template with given data,
+// and returns sanitized nodes which are validated against Telegraph limits.
+func (t *Template) Execute(data any) ([]Node, error) {
+	var buf bytes.Buffer
+	if err := t.executor.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	nodes, err := NewSanitizedNodesWithHTML(buf.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, ValidateContent(nodes)
+}
+
+// CreatePageWithTemplate creates a new page with the result of given template.
+func (c *Client) CreatePageWithTemplate(title, authorName, authorURL string, template *Template, data any, returnContent bool) (page Page, err error) {
+	var nodes []Node
+	if nodes, err = template.Execute(data); err == nil {
+		if err = ValidatePage(title, authorName, authorURL, nodes); err == nil {
+			return c.CreatePage(title, authorName, authorURL, nodes, returnContent)
+		}
+	}
+
+	return page, err
+}
+
+// functions available in templates (they return HTML strings with escaped arguments)
+var templateFuncs = map[string]func(args ...string) string{
+	"figure": func(args ...string) string {
+		return figureHTML(`<img src="`+htmltemplate.HTMLEscapeString(arg(args, 0))+`">`, arg(args, 1))
+	},
+	"video": func(args ...string) string {
+		return figureHTML(`<video src="`+htmltemplate.HTMLEscapeString(arg(args, 0))+`"></video>`, arg(args, 1))
+	},
+	"embed": func(args ...string) string {
+		return RenderHTML([]Node{NewEmbed(EmbedType(arg(args, 0)), arg(args, 1), arg(args, 2))})
+	},
+	"aside": func(args ...string) string {
+		return "<aside>" + htmltemplate.HTMLEscapeString(arg(args, 0)) + "</aside>"
+	},
+}
+
+// figure HTML with an optional caption
+func figureHTML(inner, caption string) string {
+	if caption != "" {
+		inner += "<figcaption>" + htmltemplate.HTMLEscapeString(caption) + "</figcaption>"
+	}
+
+	return "<figure>" + inner + "</figure>"
+}
+
+// nth argument, or an empty string
+func arg(args []string, n int) string {
+	if n < len(args) {
+		return args[n]
+	}
+
+	return ""
+}
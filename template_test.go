@@ -0,0 +1,42 @@
+package telegraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := NewHTMLTemplate("notes", `<div><h1>{{.Version}}</h1><script>alert(1)</script><p class="x">{{.Note}}</p>{{figure .Image "screenshot"}}{{embed "youtube" .Video}}{{aside "thanks"}}</div>`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %s", err)
+	}
+
+	nodes, err := tmpl.Execute(map[string]string{
+		"Version": "v1.0",
+		"Note":    "<b>escaped</b>",
+		"Image":   "https://example.com/a.png",
+		"Video":   "https://youtu.be/abc",
+	})
+	if err != nil {
+		t.Fatalf("failed to execute template: %s", err)
+	}
+
+	expected := `<h3>v1.0</h3><p>&lt;b&gt;escaped&lt;/b&gt;</p><figure><img src="https://example.com/a.png"><figcaption>screenshot</figcaption></figure><figure><iframe src="/embed/youtube?url=https%3A%2F%2Fyoutu.be%2Fabc"></iframe></figure><aside>thanks</aside>`
+	if html := RenderHTML(nodes); html != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", html, expected)
+	}
+
+	// text/template does not escape data
+	tmpl, _ = NewTextTemplate("raw", `<p>{{.}}</p>`)
+	if nodes, err = tmpl.Execute("<b>bold</b>"); err != nil || RenderHTML(nodes) != "<p><b>bold</b></p>" {
+		t.Errorf("unexpected result: %s, %v", RenderHTML(nodes), err)
+	}
+
+	// limits
+	if _, err = tmpl.Execute(strings.Repeat("x", MaxContentSize)); err == nil {
+		t.Errorf("should fail with too large content")
+	}
+	if err = ValidatePage("", "", "", nil); err == nil {
+		t.Errorf("should fail with an empty title")
+	}
+}
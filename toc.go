@@ -0,0 +1,114 @@
+package telegraph
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Table of contents
+//
+// Telegraph generates anchors of h3/h4 headings from their texts,
+// so headings can be linked with "#anchor" in the same page.
+
+// TOCMarker is the default marker which is replaced with a table of contents by InsertTableOfContents.
+const TOCMarker = "[TOC]"
+
+// HeadingAnchor returns the anchor which Telegraph generates for a heading with given text:
+// whitespaces are replaced with '-', and characters which are not allowed in url fragments are removed.
+func HeadingAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.Join(strings.Fields(text), " ") {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		case strings.ContainsRune("\"#%<>?[\\]^`{|}", r) || unicode.IsControl(r):
+			continue
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// TableOfContents returns a list of links to h3/h4 headings in given nodes (h4 ones nested in h3 ones),
+// or nil if there is no heading.
+func TableOfContents(nodes []Node) Node {
+	items := []Node{}
+
+	lastH3 := -1 // index of the last h3 item
+	for _, heading := range headings(nodes) {
+		text := strings.TrimSpace(NodesText(heading.Children))
+		item := NodeElement{Tag: "li", Children: []Node{
+			NodeElement{Tag: "a", Attrs: map[string]string{"href": "#" + HeadingAnchor(text)}, Children: []Node{text}},
+		}}
+
+		if heading.Tag == "h4" && lastH3 >= 0 { // nest in the last h3 item
+			parent := items[lastH3].(NodeElement)
+			if len(parent.Children) == 1 {
+				parent.Children = append(parent.Children, NodeElement{Tag: "ul"})
+			}
+			nested := parent.Children[1].(NodeElement)
+			nested.Children = append(nested.Children, item)
+			parent.Children[1] = nested
+			items[lastH3] = parent
+			continue
+		}
+
+		items = append(items, item)
+		if heading.Tag == "h3" {
+			lastH3 = len(items) - 1
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	return NodeElement{Tag: "ul", Children: items}
+}
+
+// InsertTableOfContents replaces a top-level paragraph (or text) which only has `marker` (eg. TOCMarker)
+// with the table of contents of given nodes, and returns the result with whether the marker was found.
+func InsertTableOfContents(nodes []Node, marker string) (inserted []Node, found bool) {
+	toc := TableOfContents(nodes)
+
+	inserted = []Node{}
+	for _, node := range nodes {
+		switch n := normalizeNode(node).(type) {
+		case string:
+			if strings.TrimSpace(n) == marker {
+				found = true
+				if toc != nil {
+					inserted = append(inserted, toc)
+				}
+				continue
+			}
+		case NodeElement:
+			if n.Tag == "p" && strings.TrimSpace(NodesText(n.Children)) == marker {
+				found = true
+				if toc != nil {
+					inserted = append(inserted, toc)
+				}
+				continue
+			}
+		}
+		inserted = append(inserted, node)
+	}
+
+	return inserted, found
+}
+
+// h3/h4 headings in given nodes, in order
+func headings(nodes []Node) (found []NodeElement) {
+	for _, node := range nodes {
+		if element, ok := normalizeNode(node).(NodeElement); ok {
+			if element.Tag == "h3" || element.Tag == "h4" {
+				found = append(found, element)
+			} else {
+				found = append(found, headings(element.Children)...)
+			}
+		}
+	}
+
+	return found
+}
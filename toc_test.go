@@ -0,0 +1,20 @@
+package telegraph
+
+import "testing"
+
+func TestTableOfContents(t *testing.T) {
+	if anchor := HeadingAnchor(" What's  new in <v2>? "); anchor != "What's-new-in-v2" {
+		t.Errorf("unexpected anchor: %s", anchor)
+	}
+
+	nodes, _ := NewNodesWithMarkdown("[TOC]\n\n#### Intro\n\n## First\n\n### Detail\n\n## Second")
+	inserted, found := InsertTableOfContents(nodes, TOCMarker)
+	if !found {
+		t.Fatalf("marker not found")
+	}
+
+	expected := `<ul><li><a href="#Intro">Intro</a></li><li><a href="#First">First</a><ul><li><a href="#Detail">Detail</a></li></ul></li><li><a href="#Second">Second</a></li></ul>`
+	if html := RenderHTML(inserted[:1]); html != expected {
+		t.Errorf("unexpected toc:\n%s\nexpected:\n%s", html, expected)
+	}
+}
@@ -0,0 +1,217 @@
+package telegraph
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Access token stores
+
+// ErrNoAccessToken is returned when there is no access token in a TokenStore.
+var ErrNoAccessToken = errors.New("no access token in the store")
+
+// TokenStore is an interface for loading and saving access tokens.
+type TokenStore interface {
+	// Load returns the stored access token, or ErrNoAccessToken if there is none.
+	Load() (token string, err error)
+
+	// Save stores given access token, replacing the existing one.
+	Save(token string) error
+}
+
+////////////////
+// in-memory store
+
+// MemoryTokenStore is a TokenStore which keeps the access token in memory.
+type MemoryTokenStore struct {
+	mu    sync.RWMutex
+	token string
+}
+
+// NewMemoryTokenStore creates a new MemoryTokenStore with given access token (can be empty).
+func NewMemoryTokenStore(token string) *MemoryTokenStore {
+	return &MemoryTokenStore{token: token}
+}
+
+// Load returns the stored access token.
+func (s *MemoryTokenStore) Load() (token string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.token == "" {
+		return "", ErrNoAccessToken
+	}
+	return s.token, nil
+}
+
+// Save stores given access token.
+func (s *MemoryTokenStore) Save(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+	return nil
+}
+
+////////////////
+// file store
+
+// constants for encrypted token files
+const (
+	encryptedTokenPrefix = "telegraph-go:enc:v1:"
+	encryptionSaltSize   = 16
+	encryptionKeySize    = 32 // AES-256
+	encryptionIterations = 600000
+)
+
+// FileTokenStore is a TokenStore which keeps the access token in a file,
+// optionally encrypted with a passphrase.
+type FileTokenStore struct {
+	mu         sync.Mutex
+	path       string
+	passphrase string
+}
+
+// NewFileTokenStore creates a new FileTokenStore which saves the access token in plain text.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// NewEncryptedFileTokenStore creates a new FileTokenStore which saves the access token
+// encrypted (AES-GCM) with a key derived from given passphrase.
+func NewEncryptedFileTokenStore(path, passphrase string) *FileTokenStore {
+	return &FileTokenStore{path: path, passphrase: passphrase}
+}
+
+// Load reads the access token from the file.
+func (s *FileTokenStore) Load() (token string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bytes []byte
+	if bytes, err = os.ReadFile(s.path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrNoAccessToken
+		}
+		return "", fmt.Errorf("failed to read token file '%s': %s", s.path, err)
+	}
+
+	token = strings.TrimSpace(string(bytes))
+	if s.passphrase != "" {
+		if token, err = decryptToken(token, s.passphrase); err != nil {
+			return "", fmt.Errorf("failed to decrypt token file '%s': %s", s.path, err)
+		}
+	} else if strings.HasPrefix(token, encryptedTokenPrefix) {
+		return "", fmt.Errorf("token file '%s' is encrypted, but no passphrase was given", s.path)
+	}
+
+	if token == "" {
+		return "", ErrNoAccessToken
+	}
+	return token, nil
+}
+
+// Save writes given access token to the file atomically.
+func (s *FileTokenStore) Save(token string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.passphrase != "" {
+		if token, err = encryptToken(token, s.passphrase); err != nil {
+			return fmt.Errorf("failed to encrypt token: %s", err)
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(s.path), 0700); err == nil {
+		tmp := s.path + ".tmp"
+		if err = os.WriteFile(tmp, []byte(token+"\n"), 0600); err == nil {
+			if err = os.Rename(tmp, s.path); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to write token file '%s': %s", s.path, err)
+}
+
+// encrypt token with passphrase
+//
+// format: prefix + base64(salt + nonce + ciphertext)
+func encryptToken(token, passphrase string) (string, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	gcm, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(append(salt, nonce...), nonce, []byte(token), nil)
+
+	return encryptedTokenPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt token with passphrase
+func decryptToken(encrypted, passphrase string) (string, error) {
+	if !strings.HasPrefix(encrypted, encryptedTokenPrefix) {
+		return "", errors.New("not an encrypted token")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedTokenPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < encryptionSaltSize {
+		return "", errors.New("encrypted token is too short")
+	}
+
+	gcm, err := newTokenCipher(passphrase, sealed[:encryptionSaltSize])
+	if err != nil {
+		return "", err
+	}
+
+	sealed = sealed[encryptionSaltSize:]
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted token is too short")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("wrong passphrase or corrupted token")
+	}
+
+	return string(plain), nil
+}
+
+// create AES-GCM cipher with a key derived from passphrase and salt
+func newTokenCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, encryptionIterations, encryptionKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
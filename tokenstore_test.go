@@ -0,0 +1,102 @@
+package telegraph
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemoryTokenStore(t *testing.T) {
+	store := NewMemoryTokenStore("")
+
+	if _, err := store.Load(); !errors.Is(err, ErrNoAccessToken) {
+		t.Errorf("expected ErrNoAccessToken, but got: %v", err)
+	}
+	if err := store.Save("token"); err != nil {
+		t.Fatalf("failed to save token: %s", err)
+	}
+	if token, err := store.Load(); err != nil || token != "token" {
+		t.Errorf("unexpected token: %s, %v", token, err)
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	store := NewFileTokenStore(path)
+
+	if _, err := store.Load(); !errors.Is(err, ErrNoAccessToken) {
+		t.Errorf("expected ErrNoAccessToken, but got: %v", err)
+	}
+	if err := store.Save("plain-token"); err != nil {
+		t.Fatalf("failed to save token: %s", err)
+	}
+	if token, err := NewFileTokenStore(path).Load(); err != nil || token != "plain-token" {
+		t.Errorf("unexpected token: %s, %v", token, err)
+	}
+}
+
+func TestEncryptedFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	store := NewEncryptedFileTokenStore(path, "passphrase")
+
+	if err := store.Save("secret-token"); err != nil {
+		t.Fatalf("failed to save token: %s", err)
+	}
+
+	if bytes, err := os.ReadFile(path); err != nil || strings.Contains(string(bytes), "secret-token") {
+		t.Errorf("token is not encrypted: %s, %v", bytes, err)
+	}
+	if token, err := NewEncryptedFileTokenStore(path, "passphrase").Load(); err != nil || token != "secret-token" {
+		t.Errorf("unexpected token: %s, %v", token, err)
+	}
+	if _, err := NewEncryptedFileTokenStore(path, "wrong").Load(); err == nil {
+		t.Errorf("should fail with wrong passphrase")
+	}
+	if _, err := NewFileTokenStore(path).Load(); err == nil {
+		t.Errorf("should fail without passphrase")
+	}
+}
+
+func TestClientWithTokenStore(t *testing.T) {
+	account := `{"ok":true,"result":{"short_name":"sample","access_token":"created-token"}}`
+
+	// created account is saved
+	store := NewMemoryTokenStore("")
+	transport := &cannedTransport{responses: []string{account}}
+	if client, err := Create("sample", "", "", WithTokenStore(store), WithHTTPClient(&http.Client{Transport: transport})); err != nil || client.AccessToken() != "created-token" {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if token, err := store.Load(); err != nil || token != "created-token" {
+		t.Errorf("created access token was not saved: %s, %v", token, err)
+	}
+
+	// verified access token is saved
+	store = NewMemoryTokenStore("")
+	transport = &cannedTransport{responses: []string{account}}
+	if _, err := Load("given-token", WithTokenStore(store), WithHTTPClient(&http.Client{Transport: transport})); err != nil {
+		t.Fatalf("failed to load client: %s", err)
+	}
+	if token, err := store.Load(); err != nil || token != "given-token" {
+		t.Errorf("loaded access token was not saved: %s, %v", token, err)
+	}
+
+	// access token is read from the store
+	transport = &cannedTransport{responses: []string{account}}
+	client, err := LoadFromStore(store, WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil || client.AccessToken() != "given-token" {
+		t.Fatalf("failed to load client from store: %v", err)
+	}
+	if form, _ := url.ParseQuery(transport.bodies[0]); form.Get("access_token") != "given-token" {
+		t.Errorf("access token in the store was not used: %#+v", form)
+	}
+
+	// no access token in the store
+	transport = &cannedTransport{responses: []string{account}}
+	if _, err := LoadFromStore(NewMemoryTokenStore(""), WithHTTPClient(&http.Client{Transport: transport})); !errors.Is(err, ErrNoAccessToken) || transport.requests != 0 {
+		t.Errorf("unexpected error for an empty store: %v (%d requests)", err, transport.requests)
+	}
+}
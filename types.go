@@ -1,5 +1,12 @@
 package telegraph
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Various types
 
 ////////////////
@@ -42,6 +49,52 @@ type NodeElement struct {
 	Children []Node            `json:"children,omitempty"`
 }
 
+// convert nodes decoded from JSON (map[string]any) to NodeElements recursively
+func normalizeNodes(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+
+	normalized := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		normalized = append(normalized, normalizeNode(node))
+	}
+
+	return normalized
+}
+
+// convert a node decoded from JSON (map[string]any) to NodeElement recursively
+func normalizeNode(node Node) Node {
+	switch n := node.(type) {
+	case NodeElement:
+		n.Children = normalizeNodes(n.Children)
+		return n
+	case *NodeElement:
+		if n != nil {
+			return normalizeNode(*n)
+		}
+	case map[string]any:
+		element := NodeElement{}
+		element.Tag, _ = n["tag"].(string)
+		if attrs, ok := n["attrs"].(map[string]any); ok {
+			element.Attrs = map[string]string{}
+			for key, value := range attrs {
+				if str, ok := value.(string); ok {
+					element.Attrs[key] = str
+				}
+			}
+		}
+		if children, ok := n["children"].([]any); ok {
+			for _, child := range children {
+				element.Children = append(element.Children, normalizeNode(child))
+			}
+		}
+		return element
+	}
+
+	return node
+}
+
 // Page type
 //
 // http://telegra.ph/api#Page
@@ -72,3 +125,57 @@ type PageList struct {
 type PageViews struct {
 	Views int `json:"views"`
 }
+
+////////////////
+// errors
+
+// FloodWaitError is returned when the API responds with FLOOD_WAIT_X error.
+type FloodWaitError struct {
+	RetryAfter time.Duration
+}
+
+// Error returns the error message.
+func (e *FloodWaitError) Error() string {
+	return fmt.Sprintf("erroneous response: FLOOD_WAIT_%d", int(e.RetryAfter/time.Second))
+}
+
+// APIError is returned when the API responds with an error.
+type APIError struct {
+	Message string
+}
+
+// Error returns the error message.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("erroneous response: %s", e.Message)
+}
+
+// create an error with the error message of an API response
+func newAPIError(message string) error {
+	if strings.HasPrefix(message, "FLOOD_WAIT_") {
+		if seconds, err := strconv.Atoi(strings.TrimPrefix(message, "FLOOD_WAIT_")); err == nil {
+			return &FloodWaitError{RetryAfter: time.Duration(seconds) * time.Second}
+		}
+	}
+
+	return &APIError{Message: message}
+}
+
+// error while sending a request or receiving its response
+type networkError struct {
+	error
+}
+
+// Unwrap returns the wrapped error.
+func (e *networkError) Unwrap() error {
+	return e.error
+}
+
+// error while decoding a response
+type decodeError struct {
+	error
+}
+
+// Unwrap returns the wrapped error.
+func (e *decodeError) Unwrap() error {
+	return e.error
+}
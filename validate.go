@@ -0,0 +1,55 @@
+package telegraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Validation of Telegraph limits
+//
+// http://telegra.ph/api#createPage
+
+// limits of Telegraph API
+const (
+	MaxShortNameLength  = 32
+	MaxAuthorNameLength = 128
+	MaxAuthorURLLength  = 512
+	MaxTitleLength      = 256
+	MaxContentSize      = 64 * 1024 // in bytes of JSON-encoded content
+)
+
+// ValidatePage checks if given page parameters are within Telegraph limits,
+// and returns all violations as a joined error.
+func ValidatePage(title, authorName, authorURL string, content []Node) error {
+	errs := []error{}
+
+	if length := utf8.RuneCountInString(title); length < 1 || length > MaxTitleLength {
+		errs = append(errs, fmt.Errorf("title should be 1-%d characters (was %d)", MaxTitleLength, length))
+	}
+	if length := utf8.RuneCountInString(authorName); length > MaxAuthorNameLength {
+		errs = append(errs, fmt.Errorf("author name should be 0-%d characters (was %d)", MaxAuthorNameLength, length))
+	}
+	if length := utf8.RuneCountInString(authorURL); length > MaxAuthorURLLength {
+		errs = append(errs, fmt.Errorf("author url should be 0-%d characters (was %d)", MaxAuthorURLLength, length))
+	}
+	if err := ValidateContent(content); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateContent checks if given content is within Telegraph limits.
+func ValidateContent(content []Node) error {
+	bytes, err := json.Marshal(castNodes(defaultLogger(), content))
+	if err != nil {
+		return fmt.Errorf("content cannot be encoded: %s", err)
+	}
+	if len(bytes) > MaxContentSize {
+		return fmt.Errorf("content should be at most %d bytes (was %d)", MaxContentSize, len(bytes))
+	}
+
+	return nil
+}